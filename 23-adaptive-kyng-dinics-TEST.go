@@ -6,9 +6,19 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"testing"
 	"time"
 )
 
@@ -89,4 +99,739 @@ func runStressTests() {
 	
 	fmt.Println("\n✅ STRESS TEST COMPLETE")
 	fmt.Printf("📈 All tests use the main implementation from 23-adaptive-kyng-dinics-algorithm.go\n")
-}
\ No newline at end of file
+}
+
+// ============================================================================
+// CORRECTNESS TESTS
+// ============================================================================
+
+func TestDisjointPaths(t *testing.T) {
+	// Two routes share vertex 3; edge-disjoint allows both, vertex-disjoint one
+	graph := NewAdaptiveGraph(6)
+	graph.AddEdge(0, 1, 5)
+	graph.AddEdge(0, 2, 5)
+	graph.AddEdge(1, 3, 5)
+	graph.AddEdge(2, 3, 5)
+	graph.AddEdge(3, 4, 5)
+	graph.AddEdge(3, 5, 5)
+	graph.AddEdge(4, 5, 5)
+
+	tests := []struct {
+		name     string
+		mode     DisjointMode
+		k        int
+		expected int
+	}{
+		{"Edges", DisjointEdges, 10, 2},
+		{"Edges_Limited", DisjointEdges, 1, 1},
+		{"Vertices", DisjointVertices, 10, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := graph.DisjointPaths(0, 5, tt.k, tt.mode)
+			if len(paths) != tt.expected {
+				t.Fatalf("DisjointPaths returned %d paths, want %d: %v", len(paths), tt.expected, paths)
+			}
+
+			usedEdges := make(map[[2]int]bool)
+			usedVertices := make(map[int]bool)
+			for _, path := range paths {
+				if path[0] != 0 || path[len(path)-1] != 5 {
+					t.Errorf("Path %v does not run from 0 to 5", path)
+				}
+				for i := 1; i < len(path); i++ {
+					edge := [2]int{path[i-1], path[i]}
+					if usedEdges[edge] {
+						t.Errorf("Edge %v used by more than one path", edge)
+					}
+					usedEdges[edge] = true
+				}
+				for _, v := range path[1 : len(path)-1] {
+					if tt.mode == DisjointVertices && usedVertices[v] {
+						t.Errorf("Vertex %d used by more than one path", v)
+					}
+					usedVertices[v] = true
+				}
+			}
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	costMatrix := [][]int{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	}
+	rowToCol, cost := Assign(costMatrix)
+	if cost != 5 {
+		t.Errorf("Assign cost = %d, want 5 (assignment %v)", cost, rowToCol)
+	}
+
+	// Dense Hungarian and sparse min-cost flow must agree with brute force
+	rng := rand.New(rand.NewSource(26))
+	for trial := 0; trial < 50; trial++ {
+		rows, cols := rng.Intn(5)+1, rng.Intn(5)+1
+		matrix := make([][]int, rows)
+		var edges []AssignmentEdge
+		for i := range matrix {
+			matrix[i] = make([]int, cols)
+			for j := range matrix[i] {
+				matrix[i][j] = rng.Intn(21) - 5
+				edges = append(edges, AssignmentEdge{Row: i, Col: j, Cost: matrix[i][j]})
+			}
+		}
+
+		expected := bruteForceAssignment(matrix, 0, make([]bool, cols))
+		if _, got := Assign(matrix); got != expected {
+			t.Errorf("Trial %d: Assign cost = %d, want %d for %v", trial, got, expected, matrix)
+		}
+		if _, got := AssignSparse(rows, cols, edges); got != expected {
+			t.Errorf("Trial %d: AssignSparse cost = %d, want %d for %v", trial, got, expected, matrix)
+		}
+	}
+}
+
+// bruteForceAssignment tries every matching of min(rows, cols) pairs
+func bruteForceAssignment(matrix [][]int, row int, usedCols []bool) int {
+	rows, cols := len(matrix), len(usedCols)
+	if row == rows {
+		return 0
+	}
+	best := math.MaxInt32
+	free := 0
+	for _, used := range usedCols {
+		if !used {
+			free++
+		}
+	}
+	// A row may stay unmatched only when rows outnumber columns
+	if rows-row > free {
+		best = bruteForceAssignment(matrix, row+1, usedCols)
+	}
+	for j := 0; j < cols; j++ {
+		if !usedCols[j] {
+			usedCols[j] = true
+			best = min(best, matrix[row][j]+bruteForceAssignment(matrix, row+1, usedCols))
+			usedCols[j] = false
+		}
+	}
+	return best
+}
+
+func TestMaxClosure(t *testing.T) {
+	// Features 0 and 1 pay off but both need infrastructure item 2
+	weights := []int{6, 4, -7, -3, 2}
+	deps := [][2]int{{0, 2}, {1, 2}, {4, 3}}
+
+	chosen, profit := MaxClosure(weights, deps)
+	if profit != 3 {
+		t.Errorf("MaxClosure profit = %d, want 3", profit)
+	}
+	expected := []int{0, 1, 2}
+	if fmt.Sprint(chosen) != fmt.Sprint(expected) {
+		t.Errorf("MaxClosure chose %v, want %v", chosen, expected)
+	}
+
+	// Cross-check against enumeration of every closed subset
+	rng := rand.New(rand.NewSource(28))
+	for trial := 0; trial < 50; trial++ {
+		n := rng.Intn(8) + 1
+		weights := make([]int, n)
+		for i := range weights {
+			weights[i] = rng.Intn(21) - 10
+		}
+		var deps [][2]int
+		for d := rng.Intn(2 * n); d > 0; d-- {
+			deps = append(deps, [2]int{rng.Intn(n), rng.Intn(n)})
+		}
+
+		best := 0
+		for mask := 0; mask < 1<<n; mask++ {
+			closed := true
+			for _, dep := range deps {
+				if mask&(1<<dep[0]) != 0 && mask&(1<<dep[1]) == 0 {
+					closed = false
+					break
+				}
+			}
+			if !closed {
+				continue
+			}
+			total := 0
+			for i := 0; i < n; i++ {
+				if mask&(1<<i) != 0 {
+					total += weights[i]
+				}
+			}
+			best = max(best, total)
+		}
+
+		chosen, profit := MaxClosure(weights, deps)
+		if profit != best {
+			t.Errorf("Trial %d: MaxClosure profit = %d, want %d", trial, profit, best)
+		}
+		total := 0
+		for _, i := range chosen {
+			total += weights[i]
+		}
+		if total != profit {
+			t.Errorf("Trial %d: chosen set %v weighs %d, reported %d", trial, chosen, total, profit)
+		}
+	}
+}
+
+// denseGraphPair builds two copies of a random dense graph, one to solve
+// adaptively and one as a standard Dinic's reference
+func denseGraphPair(rng *rand.Rand, vertices int, density float64) (*AdaptiveGraph, *AdaptiveGraph) {
+	graph := NewAdaptiveGraph(vertices)
+	reference := NewAdaptiveGraph(vertices)
+	for u := 0; u < vertices; u++ {
+		for v := 0; v < vertices; v++ {
+			if u != v && rng.Float64() < density {
+				capacity := rng.Intn(50) + 1
+				graph.AddEdge(u, v, capacity)
+				reference.AddEdge(u, v, capacity)
+			}
+		}
+	}
+	return graph, reference
+}
+
+func TestMinCutArbitraryTerminals(t *testing.T) {
+	rng := rand.New(rand.NewSource(28))
+	for trial := 0; trial < 10; trial++ {
+		graph, reference := denseGraphPair(rng, 120, 0.6)
+		source := rng.Intn(120)
+		sink := (source + 1 + rng.Intn(119)) % 120
+
+		expected := reference.standardDinicsMaxFlow(source, sink)
+		got, sourceSide := graph.MinCut(source, sink)
+		if graph.algorithm != AlgoPushRelabel {
+			t.Fatalf("Dense graph selected algorithm %d, want push-relabel", graph.algorithm)
+		}
+		if got != expected {
+			t.Errorf("Trial %d (%d->%d): MinCut flow = %d, want %d", trial, source, sink, got, expected)
+			continue
+		}
+		if !sourceSide[source] || sourceSide[sink] {
+			t.Errorf("Trial %d: cut does not separate %d from %d", trial, source, sink)
+		}
+		cut := 0
+		for u := range graph.AdjacencyList {
+			for _, edge := range graph.AdjacencyList[u] {
+				if edge.Original && sourceSide[u] && !sourceSide[edge.To] {
+					cut += edge.Capacity
+				}
+			}
+		}
+		if cut != got {
+			t.Errorf("Trial %d: cut capacity = %d, want %d", trial, cut, got)
+		}
+	}
+}
+
+func TestBoykovKolmogorov(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	for trial := 0; trial < 100; trial++ {
+		vertices := rng.Intn(30) + 2
+		reference := NewAdaptiveGraph(vertices)
+		bk := NewAdaptiveGraph(vertices)
+		for e := rng.Intn(vertices * 4); e > 0; e-- {
+			from, to, capacity := rng.Intn(vertices), rng.Intn(vertices), rng.Intn(20)+1
+			if from == to {
+				continue
+			}
+			reference.AddEdge(from, to, capacity)
+			bk.AddEdge(from, to, capacity)
+		}
+
+		expected := reference.standardDinicsMaxFlow(0, vertices-1)
+		if got := bk.boykovKolmogorovMaxFlow(0, vertices-1); got != expected {
+			t.Errorf("Trial %d: Boykov-Kolmogorov flow = %d, want %d", trial, got, expected)
+		}
+	}
+}
+
+func TestSegmentGrid(t *testing.T) {
+	// Left half prefers foreground, right half background, one noisy pixel
+	width, height := 8, 6
+	foreground := make([]int, width*height)
+	background := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := y*width + x
+			if x < width/2 {
+				foreground[p], background[p] = 9, 1
+			} else {
+				foreground[p], background[p] = 1, 9
+			}
+		}
+	}
+	noisy := 2*width + 6
+	foreground[noisy], background[noisy] = 6, 4
+
+	for _, connectivity := range []GridConnectivity{Grid4Connected, Grid8Connected} {
+		labels, energy := SegmentGrid(width, height, foreground, background, 5, connectivity)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if labels[y*width+x] != (x < width/2) {
+					t.Errorf("%d-connected: pixel (%d,%d) labelled %v", connectivity, x, y, labels[y*width+x])
+				}
+			}
+		}
+		if energy <= 0 {
+			t.Errorf("%d-connected: energy = %d, want positive", connectivity, energy)
+		}
+	}
+
+	// Large grid graphs should route to Boykov-Kolmogorov
+	size := 40
+	graph := NewAdaptiveGraph(size*size + 2)
+	for p := 1; p <= size*size; p++ {
+		graph.AddEdge(0, p, rand.Intn(10)+1)
+		graph.AddEdge(p, size*size+1, rand.Intn(10)+1)
+		if p%size != 0 {
+			graph.AddEdge(p, p+1, 3)
+			graph.AddEdge(p+1, p, 3)
+		}
+		if p+size <= size*size {
+			graph.AddEdge(p, p+size, 3)
+			graph.AddEdge(p+size, p, 3)
+		}
+	}
+	graph.analyzeGraph()
+	if graph.algorithm != AlgoBoykovKolmogorov {
+		t.Errorf("Grid graph selected algorithm %d, want Boykov-Kolmogorov", graph.algorithm)
+	}
+}
+
+func TestReducedMaxFlow(t *testing.T) {
+	rng := rand.New(rand.NewSource(30))
+	for trial := 0; trial < 200; trial++ {
+		vertices := rng.Intn(40) + 2
+		reference := NewAdaptiveGraph(vertices)
+		reduced := NewAdaptiveGraph(vertices)
+		reduced.ReductionEnabled = true
+
+		addEdge := func(from, to, capacity int) {
+			reference.AddEdge(from, to, capacity)
+			reduced.AddEdge(from, to, capacity)
+		}
+		// Chains, parallel edges, dead ends and infinite cycles
+		for e := rng.Intn(vertices * 3); e > 0; e-- {
+			from, to := rng.Intn(vertices), rng.Intn(vertices)
+			if from == to {
+				continue
+			}
+			switch rng.Intn(6) {
+			case 0:
+				addEdge(from, to, INFINITE_CAPACITY)
+				addEdge(to, from, INFINITE_CAPACITY)
+			case 1:
+				addEdge(from, to, rng.Intn(10)+1)
+				addEdge(from, to, rng.Intn(10)+1)
+			default:
+				addEdge(from, to, rng.Intn(20)+1)
+			}
+		}
+		source := rng.Intn(vertices)
+		sink := (source + 1 + rng.Intn(vertices-1)) % vertices
+
+		expected := reference.standardDinicsMaxFlow(source, sink)
+		if expected >= INFINITE_CAPACITY {
+			continue
+		}
+		got, sourceSide := reduced.MinCut(source, sink)
+		if got != expected {
+			t.Errorf("Trial %d: reduced flow = %d, want %d", trial, got, expected)
+			continue
+		}
+
+		// Mapped flow must be feasible, conserved and match the cut
+		net := make([]int, vertices)
+		cut := 0
+		for u := 0; u < vertices; u++ {
+			for _, edge := range reduced.AdjacencyList[u] {
+				if !edge.Original {
+					continue
+				}
+				if edge.Flow < 0 || edge.Flow > edge.Capacity {
+					t.Errorf("Trial %d: edge %d->%d flow %d exceeds capacity %d", trial, u, edge.To, edge.Flow, edge.Capacity)
+				}
+				net[u] -= edge.Flow
+				net[edge.To] += edge.Flow
+				if sourceSide[u] && !sourceSide[edge.To] {
+					cut += edge.Capacity
+				}
+			}
+		}
+		for v := 0; v < vertices; v++ {
+			if v != source && v != sink && net[v] != 0 {
+				t.Errorf("Trial %d: vertex %d violates conservation by %d", trial, v, net[v])
+			}
+		}
+		if net[sink] != got || cut != got {
+			t.Errorf("Trial %d: sink receives %d, cut %d, flow %d", trial, net[sink], cut, got)
+		}
+	}
+}
+
+func TestReducedMaxFlowInfinite(t *testing.T) {
+	graph := NewAdaptiveGraph(4)
+	graph.ReductionEnabled = true
+	graph.AddEdge(0, 1, 5)
+	graph.AddEdge(1, 3, 3)
+	graph.AddEdge(0, 2, 4)
+	if got := graph.MaxFlow(0, 3); got != 3 {
+		t.Fatalf("MaxFlow = %d, want 3", got)
+	}
+
+	// An infinite cycle through both terminals leaves no finite cut
+	for _, pair := range [][2]int{{0, 2}, {2, 0}, {2, 3}, {3, 2}} {
+		graph.AddEdge(pair[0], pair[1], INFINITE_CAPACITY)
+	}
+	flow, sourceSide := graph.MinCut(0, 3)
+	if flow != INFINITE_CAPACITY {
+		t.Errorf("MinCut flow = %d, want INFINITE_CAPACITY", flow)
+	}
+	if !sourceSide[3] {
+		t.Error("Sink is not on the source side of an infinite cut")
+	}
+	for u := range graph.AdjacencyList {
+		for _, edge := range graph.AdjacencyList[u] {
+			if edge.Flow != 0 {
+				t.Errorf("Edge %d->%d kept flow %d from the earlier solve", u, edge.To, edge.Flow)
+			}
+		}
+	}
+	if graph.reducedVertices != 0 || graph.reducedEdges != 0 {
+		t.Errorf("Reduced graph reported as %d vertices, %d edges", graph.reducedVertices, graph.reducedEdges)
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	build := func() *AdaptiveGraph {
+		rng := rand.New(rand.NewSource(31))
+		graph := NewAdaptiveGraph(60)
+		for e := 0; e < 300; e++ {
+			graph.AddEdge(rng.Intn(60), rng.Intn(60), rng.Intn(50)+1)
+		}
+		return graph
+	}
+	path := filepath.Join(t.TempDir(), "maxflow.ckpt")
+
+	expected := build().standardDinicsMaxFlow(0, 59)
+
+	// Full run with checkpoints leaves a final snapshot behind
+	checkpointed := build()
+	checkpointed.CheckpointPath = path
+	if got := checkpointed.MaxFlow(0, 59); got != expected {
+		t.Fatalf("Checkpointed MaxFlow = %d, want %d", got, expected)
+	}
+	if err := checkpointed.CheckpointError(); err != nil {
+		t.Fatalf("Checkpoint write failed: %v", err)
+	}
+
+	// Simulate preemption after the first BFS phase
+	interrupted := build()
+	interrupted.CheckpointPath = path
+	interrupted.buildLevelGraphSequential(0, 59)
+	phaseFlow := 0
+	for {
+		flow := interrupted.simpleDFS(0, 59, math.MaxInt32)
+		if flow == 0 {
+			break
+		}
+		phaseFlow += flow
+	}
+	interrupted.bfsIterations++
+	if err := interrupted.writeCheckpoint(0, 59, phaseFlow); err != nil {
+		t.Fatalf("writeCheckpoint failed: %v", err)
+	}
+
+	resumed := build()
+	got, err := resumed.ResumeMaxFlow(path)
+	if err != nil {
+		t.Fatalf("ResumeMaxFlow failed: %v", err)
+	}
+	if got != expected {
+		t.Errorf("Resumed MaxFlow = %d, want %d (first phase pushed %d)", got, expected, phaseFlow)
+	}
+
+	// A flipped byte must be detected
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)/2] ^= 0xFF
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := build().ResumeMaxFlow(path); err == nil {
+		t.Error("ResumeMaxFlow accepted a corrupt checkpoint")
+	}
+
+	// A different graph must be rejected
+	other := NewAdaptiveGraph(60)
+	other.AddEdge(0, 59, 1)
+	if err := interrupted.writeCheckpoint(0, 59, phaseFlow); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.ResumeMaxFlow(path); err == nil {
+		t.Error("ResumeMaxFlow accepted a checkpoint from another graph")
+	}
+
+	// Edges added after the first snapshot change the fingerprint: two
+	// graphs grown differently from the same start must not match
+	grown := build()
+	grown.CheckpointPath = path
+	grown.MaxFlow(0, 59)
+	grown.AddEdge(0, 59, 1)
+	other = build()
+	other.CheckpointPath = filepath.Join(t.TempDir(), "other.ckpt")
+	other.MaxFlow(0, 59)
+	other.AddEdge(1, 58, 1)
+	if err := grown.writeCheckpoint(0, 59, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.ResumeMaxFlow(path); err == nil {
+		t.Error("ResumeMaxFlow accepted a checkpoint written before AddEdge")
+	}
+
+	// Reduction solves a different graph, so it cannot checkpoint
+	reduced := build()
+	reduced.ReductionEnabled = true
+	reduced.CheckpointPath = filepath.Join(t.TempDir(), "reduced.ckpt")
+	if got := reduced.MaxFlow(0, 59); got != expected {
+		t.Errorf("Reduced MaxFlow = %d, want %d", got, expected)
+	}
+	if err := reduced.CheckpointError(); !errors.Is(err, errCheckpointReduction) {
+		t.Errorf("CheckpointError with reduction = %v, want %v", err, errCheckpointReduction)
+	}
+	if _, err := reduced.ResumeMaxFlow(path); !errors.Is(err, errCheckpointReduction) {
+		t.Errorf("ResumeMaxFlow with reduction = %v, want %v", err, errCheckpointReduction)
+	}
+}
+
+func TestExport(t *testing.T) {
+	graph := NewAdaptiveGraph(6)
+	graph.AddEdge(0, 1, 10)
+	graph.AddEdge(0, 2, 8)
+	graph.AddEdge(1, 3, 5)
+	graph.AddEdge(2, 3, 10)
+	graph.AddEdge(1, 4, 8)
+	graph.AddEdge(3, 4, 10)
+	graph.AddEdge(3, 5, 10)
+	graph.AddEdge(4, 5, 10)
+	maxFlow := graph.MaxFlow(0, 5)
+
+	var dot bytes.Buffer
+	if err := graph.WriteDOT(&dot, DOTOptions{}); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	for _, want := range []string{"digraph", "0 [label=\"0 (s)\"", "color=red", "style=dashed", "fillcolor=\"#9ecae1\""} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot.String())
+		}
+	}
+
+	dot.Reset()
+	if err := graph.WriteDOT(&dot, DOTOptions{TopEdges: 2}); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	if edges := strings.Count(dot.String(), "->"); edges != 2 {
+		t.Errorf("TopEdges=2 emitted %d edges", edges)
+	}
+
+	var doc exportGraph
+	var buf bytes.Buffer
+	if err := graph.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("WriteJSON produced invalid JSON: %v", err)
+	}
+	if doc.FlowValue != maxFlow || len(doc.Edges) != 8 {
+		t.Errorf("JSON flow = %d with %d edges, want %d with 8", doc.FlowValue, len(doc.Edges), maxFlow)
+	}
+	cut := 0
+	for _, e := range doc.Edges {
+		if e.Cut {
+			cut += e.Capacity
+			if !e.Saturated {
+				t.Errorf("Cut edge %d->%d is not saturated", e.From, e.To)
+			}
+		}
+	}
+	if cut != maxFlow {
+		t.Errorf("Exported cut capacity = %d, want %d", cut, maxFlow)
+	}
+}
+
+func TestExportArbitraryTerminals(t *testing.T) {
+	// Dense enough for push-relabel, with terminals away from 0 and V-1
+	graph, reference := denseGraphPair(rand.New(rand.NewSource(32)), 120, 0.6)
+	source, sink := 37, 5
+	expected := reference.standardDinicsMaxFlow(source, sink)
+	graph.MaxFlow(source, sink)
+	if graph.algorithm != AlgoPushRelabel {
+		t.Fatalf("Dense graph selected algorithm %d, want push-relabel", graph.algorithm)
+	}
+
+	var dot bytes.Buffer
+	if err := graph.WriteDOT(&dot, DOTOptions{}); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	for _, want := range []string{"37 [label=\"37 (s)\"", "5 [label=\"5 (t)\""} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output missing %q", want)
+		}
+	}
+
+	var doc exportGraph
+	var buf bytes.Buffer
+	if err := graph.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("WriteJSON produced invalid JSON: %v", err)
+	}
+	if doc.Source != source || doc.Sink != sink || doc.FlowValue != expected {
+		t.Errorf("JSON terminals %d->%d flow %d, want %d->%d flow %d", doc.Source, doc.Sink, doc.FlowValue, source, sink, expected)
+	}
+	cut := 0
+	for _, e := range doc.Edges {
+		if e.Cut {
+			cut += e.Capacity
+			if !e.Saturated {
+				t.Errorf("Cut edge %d->%d is not saturated", e.From, e.To)
+			}
+		}
+	}
+	if cut != expected {
+		t.Errorf("Exported cut capacity = %d, want %d", cut, expected)
+	}
+}
+
+func TestFlowServer(t *testing.T) {
+	server := httptest.NewServer(NewFlowServer(FlowServerConfig{
+		MaxBodyBytes:  1 << 10,
+		MaxConcurrent: 1,
+		Timeout:       time.Second,
+	}))
+	defer server.Close()
+	
+	post := func(contentType, body string) (int, map[string]interface{}) {
+		resp, err := http.Post(server.URL+"/maxflow", contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var decoded map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+			t.Fatalf("response is not JSON: %v", err)
+		}
+		return resp.StatusCode, decoded
+	}
+	
+	// Sample graph from main() with the terminals away from the first and last IDs
+	status, body := post("application/json", `{"vertices": 6, "source": 2, "sink": 0,
+		"edges": [[2,1,10],[2,3,10],[1,3,2],[1,4,4],[1,5,8],[3,5,9],[4,0,10],[5,4,6],[5,0,10]]}`)
+	if status != http.StatusOK {
+		t.Fatalf("JSON request: status %d: %v", status, body)
+	}
+	if body["flow"] != 19.0 || body["algorithm"] == "" {
+		t.Errorf("JSON request: flow %v with algorithm %v, want 19", body["flow"], body["algorithm"])
+	}
+	cut := body["minCut"].(map[string]interface{})
+	capacity := map[string]float64{"2,1": 10, "2,3": 10, "1,3": 2, "1,4": 4, "1,5": 8, "3,5": 9, "4,0": 10, "5,4": 6, "5,0": 10}
+	cutCapacity := 0.0
+	for _, e := range cut["edges"].([]interface{}) {
+		pair := e.([]interface{})
+		cutCapacity += capacity[fmt.Sprintf("%v,%v", pair[0], pair[1])]
+	}
+	if cutCapacity != 19 {
+		t.Errorf("JSON request: cut edges %v have capacity %v, want 19", cut["edges"], cutCapacity)
+	}
+	
+	dimacs := "c two paths\np max 4 4\nn 1 s\nn 4 t\na 1 2 3\na 1 3 2\na 2 4 2\na 3 4 5\n"
+	status, body = post("text/plain", dimacs)
+	if status != http.StatusOK || body["flow"] != 4.0 {
+		t.Errorf("DIMACS request: status %d, flow %v, want 4", status, body["flow"])
+	}
+	if side := body["minCut"].(map[string]interface{})["sourceSide"].([]interface{}); len(side) != 2 || side[0] != 1.0 || side[1] != 2.0 {
+		t.Errorf("DIMACS request: source side %v, want [1 2]", side)
+	}
+	
+	encoded, _ := json.Marshal(map[string]string{"dimacs": dimacs})
+	if status, body = post("application/json", string(encoded)); status != http.StatusOK || body["flow"] != 4.0 {
+		t.Errorf("embedded DIMACS: status %d, flow %v, want 4", status, body["flow"])
+	}
+	
+	for name, request := range map[string]string{
+		"same terminals": `{"vertices": 3, "source": 1, "sink": 1, "edges": []}`,
+		"edge out of range": `{"vertices": 3, "source": 0, "sink": 2, "edges": [[0,3,1]]}`,
+		"negative capacity": `{"vertices": 3, "source": 0, "sink": 2, "edges": [[0,1,-1]]}`,
+	} {
+		if status, _ := post("application/json", request); status != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want %d", name, status, http.StatusBadRequest)
+		}
+	}
+	for _, arc := range []string{"a 1 2 x", "a 1 x 2 3", "a 1 2 3 junk", "a 1 2"} {
+		if status, _ := post("text/plain", "p max 2 1\nn 1 s\nn 2 t\n"+arc+"\n"); status != http.StatusBadRequest {
+			t.Errorf("malformed DIMACS %q: status %d, want %d", arc, status, http.StatusBadRequest)
+		}
+		if _, err := parseDIMACS("p max 2 1\nn 1 s\nn 2 t\n"+arc+"\n", 10, 10); err == nil || !strings.Contains(err.Error(), "line 4") {
+			t.Errorf("parseDIMACS %q: error %v, want one naming line 4", arc, err)
+		}
+	}
+	if status, _ := post("application/json", `{"vertices": 2, "edges": [`+strings.Repeat("[0,1,1],", 200)+`[0,1,1]]}`); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body: status %d, want %d", status, http.StatusRequestEntityTooLarge)
+	}
+	
+	// DIMACS header sizes are checked before anything is allocated
+	for header, want := range map[string]int{
+		"p max 4 -1":       http.StatusBadRequest,
+		"p max -4 1":       http.StatusBadRequest,
+		"p max 4 60000000": http.StatusRequestEntityTooLarge,
+		"p max 20000000 1": http.StatusRequestEntityTooLarge,
+	} {
+		if status, _ := post("text/plain", header+"\nn 1 s\nn 4 t\n"); status != want {
+			t.Errorf("%q: status %d, want %d", header, status, want)
+		}
+	}
+}
+
+func TestFlowServerConcurrencyLimit(t *testing.T) {
+	handler := NewFlowServer(FlowServerConfig{MaxConcurrent: 1})
+	
+	// Hold the only solver slot so the request waits out its own timeout
+	<-handler.slots
+	request := httptest.NewRequest(http.MethodPost, "/maxflow",
+		strings.NewReader(`{"vertices": 2, "source": 0, "sink": 1, "edges": [[0,1,5]], "timeoutMs": 20}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("busy server: status %d, want %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+	
+	handler.slots <- struct{}{}
+	request = httptest.NewRequest(http.MethodPost, "/maxflow",
+		strings.NewReader(`{"vertices": 2, "source": 0, "sink": 1, "edges": [[0,1,5]]}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("free server: status %d, want %d", recorder.Code, http.StatusOK)
+	}
+	
+	request = httptest.NewRequest(http.MethodGet, "/maxflow", nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
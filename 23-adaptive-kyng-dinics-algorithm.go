@@ -7,9 +7,24 @@
 package main
 
 import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
 	"math"
+	"net/http"
+	"os"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,6 +45,13 @@ const (
 	SAFE_STACK_LIMIT     = 5000  // Max recursive depth before switching to iterative
 	DFS_WORK_QUEUE_SIZE  = 10000 // Work-stealing queue capacity
 	MAX_CONCURRENT_PATHS = 32    // Maximum concurrent DFS paths
+	
+	// Grid detection constants for Boykov-Kolmogorov selection
+	GRID_MAX_DEGREE      = 10     // 8 neighbours plus two terminal links
+	GRID_SAMPLE_SIZE     = 100000 // Vertices sampled when scoring grid structure
+	
+	// Capacities at or above this value are treated as never saturating
+	INFINITE_CAPACITY    = math.MaxInt32
 )
 
 // GraphType represents different categories for adaptive strategy selection
@@ -52,8 +74,45 @@ const (
 	AlgoPushRelabel
 	AlgoISAP
 	AlgoUnitCapacity
+	AlgoBoykovKolmogorov
 )
 
+// String returns the display name used in statistics and exports
+func (a FlowAlgorithm) String() string {
+	switch a {
+	case AlgoStandardDinics:
+		return "Standard Dinic's"
+	case AlgoKyngDinics:
+		return "Kyng-Dinic's (Electrical Flow)"
+	case AlgoPushRelabel:
+		return "Push-Relabel"
+	case AlgoISAP:
+		return "ISAP"
+	case AlgoUnitCapacity:
+		return "Unit Capacity Optimized"
+	case AlgoBoykovKolmogorov:
+		return "Boykov-Kolmogorov (Grid)"
+	}
+	return ""
+}
+
+// String returns the display name used in statistics and exports
+func (t GraphType) String() string {
+	switch t {
+	case GraphSmall:
+		return "Small"
+	case GraphSparse:
+		return "Sparse"
+	case GraphDense:
+		return "Dense"
+	case GraphUnitCapacity:
+		return "Unit Capacity"
+	case GraphPlanar:
+		return "Planar"
+	}
+	return ""
+}
+
 // ============================================================================
 // CORE DATA STRUCTURES
 // ============================================================================
@@ -95,6 +154,24 @@ type AdaptiveGraph struct {
 	MaxHeight     int    // Maximum height in use
 	GapOptEnabled bool   // Enable gap optimization
 	
+	// Preprocessing: solve a reduced graph and map the flow back
+	ReductionEnabled bool
+	reducedVertices  int
+	reducedEdges     int
+	
+	// Checkpointing between BFS phases (Dinic family and Boykov-Kolmogorov)
+	CheckpointPath   string // Snapshot file, empty disables checkpoints
+	CheckpointPhases int    // Phases between snapshots, 0 means every phase
+	checkpointBase   int    // Flow restored from a snapshot
+	checkpointHash   uint64 // Cached structure fingerprint
+	checkpointErr    error
+	
+	// Terminals of the last MaxFlow run (-1 before any run), used by exports
+	lastSource, lastSink int
+	
+	// Min-cost flow: per-unit edge costs parallel to AdjacencyList (nil until used)
+	EdgeCost      [][]int
+	
 	// Statistics
 	bfsIterations     int
 	dfsIterations     int
@@ -126,6 +203,9 @@ func NewAdaptiveGraph(vertices int) *AdaptiveGraph {
 		MaxHeight:     0,
 		GapOptEnabled: true,
 		
+		lastSource: -1,
+		lastSink:   -1,
+		
 		EdgePool: sync.Pool{
 			New: func() interface{} {
 				return &Edge{}
@@ -169,6 +249,30 @@ func (g *AdaptiveGraph) AddEdge(from, to, capacity int) {
 	g.AdjacencyList[from] = append(g.AdjacencyList[from], forward)
 	g.AdjacencyList[to] = append(g.AdjacencyList[to], reverse)
 	g.edges++
+	g.checkpointHash = 0 // The structure changed; fingerprint it again
+	
+	// Keep cost slots aligned once the graph carries costs
+	if g.EdgeCost != nil {
+		g.EdgeCost[from] = append(g.EdgeCost[from], 0)
+		g.EdgeCost[to] = append(g.EdgeCost[to], 0)
+	}
+}
+
+// AddEdgeWithCost adds a directed edge with a per-unit cost for min-cost flow
+func (g *AdaptiveGraph) AddEdgeWithCost(from, to, capacity, cost int) {
+	if g.EdgeCost == nil {
+		g.EdgeCost = make([][]int, g.vertices)
+		for v := range g.AdjacencyList {
+			g.EdgeCost[v] = make([]int, len(g.AdjacencyList[v]))
+		}
+	}
+	
+	forwardIdx := len(g.AdjacencyList[from])
+	g.AddEdge(from, to, capacity)
+	
+	// Reverse residual edge refunds the cost
+	g.EdgeCost[from][forwardIdx] = cost
+	g.EdgeCost[to][g.AdjacencyList[from][forwardIdx].Reverse] = -cost
 }
 
 // GraphAnalysisMetrics contains detailed graph characteristics for optimal algorithm selection
@@ -187,6 +291,7 @@ type GraphAnalysisMetrics struct {
 	BottleneckFactor    float64
 	LayeredStructure    bool
 	PlanarityScore      float64
+	GridScore           float64
 }
 
 // analyzeGraph determines optimal algorithm based on comprehensive graph characteristics
@@ -268,6 +373,7 @@ func (g *AdaptiveGraph) computeGraphMetrics() GraphAnalysisMetrics {
 	metrics.BottleneckFactor = g.computeBottleneckFactor()
 	metrics.LayeredStructure = g.detectLayeredStructure()
 	metrics.PlanarityScore = g.estimatePlanarityScore()
+	metrics.GridScore = g.computeGridScore()
 	
 	return metrics
 }
@@ -293,6 +399,9 @@ func (g *AdaptiveGraph) selectOptimalAlgorithm(metrics GraphAnalysisMetrics) Flo
 	// Unit capacity scoring
 	scores[AlgoUnitCapacity] = g.scoreUnitCapacity(metrics)
 	
+	// Boykov-Kolmogorov scoring (grid-like graphs)
+	scores[AlgoBoykovKolmogorov] = g.scoreBoykovKolmogorov(metrics)
+	
 	// Select algorithm with highest score
 	bestAlgorithm := AlgoStandardDinics
 	bestScore := scores[AlgoStandardDinics]
@@ -409,6 +518,18 @@ func (g *AdaptiveGraph) scoreUnitCapacity(metrics GraphAnalysisMetrics) float64
 	return score
 }
 
+func (g *AdaptiveGraph) scoreBoykovKolmogorov(metrics GraphAnalysisMetrics) float64 {
+	score := 0.0
+	
+	// Only consider low-degree graphs with symmetric neighbour links
+	if metrics.GridScore > 0.6 {
+		score = 350.0 // Search tree reuse beats fresh BFS phases on grids
+		score += metrics.GridScore * 200.0
+	}
+	
+	return score
+}
+
 // Helper functions for structural analysis
 func (g *AdaptiveGraph) computeVariance(values []int, mean float64) float64 {
 	if len(values) == 0 {
@@ -567,6 +688,63 @@ func (g *AdaptiveGraph) estimatePlanarityScore() float64 {
 	return 0.0 // Definitely not planar
 }
 
+func (g *AdaptiveGraph) computeGridScore() float64 {
+	// Grids (image graphs) have low-degree vertices whose neighbour links
+	// come in both directions; terminals are the only high-degree vertices
+	if g.edges == 0 {
+		return 0.0
+	}
+	
+	stride := max(1, g.vertices/GRID_SAMPLE_SIZE)
+	sampled, lowDegree := 0, 0
+	neighbourEdges, reciprocalEdges := 0, 0
+	
+	for v := 0; v < g.vertices; v += stride {
+		sampled++
+		if g.originalDegree(v) > GRID_MAX_DEGREE {
+			continue
+		}
+		lowDegree++
+		
+		for _, edge := range g.AdjacencyList[v] {
+			if !edge.Original {
+				continue
+			}
+			// Skip links into terminals (sink has no out-edges, source too many)
+			degree := g.originalDegree(edge.To)
+			if degree == 0 || degree > GRID_MAX_DEGREE {
+				continue
+			}
+			neighbourEdges++
+			for _, back := range g.AdjacencyList[edge.To] {
+				if back.Original && back.To == v {
+					reciprocalEdges++
+					break
+				}
+			}
+		}
+	}
+	
+	if neighbourEdges == 0 {
+		return 0.0
+	}
+	
+	lowDegreeRatio := float64(lowDegree) / float64(sampled)
+	reciprocalRatio := float64(reciprocalEdges) / float64(neighbourEdges)
+	return lowDegreeRatio * reciprocalRatio
+}
+
+// originalDegree counts the original (non-residual) out-edges of v
+func (g *AdaptiveGraph) originalDegree(v int) int {
+	degree := 0
+	for _, edge := range g.AdjacencyList[v] {
+		if edge.Original {
+			degree++
+		}
+	}
+	return degree
+}
+
 func (g *AdaptiveGraph) classifyGraphType(metrics GraphAnalysisMetrics) {
 	// Enhanced graph type classification based on comprehensive metrics
 	switch g.algorithm {
@@ -584,6 +762,8 @@ func (g *AdaptiveGraph) classifyGraphType(metrics GraphAnalysisMetrics) {
 		}
 	case AlgoISAP:
 		g.graphType = GraphSparse
+	case AlgoBoykovKolmogorov:
+		g.graphType = GraphPlanar
 	default:
 		g.graphType = GraphSparse
 	}
@@ -612,6 +792,7 @@ func (g *AdaptiveGraph) kyngDinicsMaxFlow(source, sink int) int {
 			totalFlow += flow
 		}
 		g.bfsIterations++
+		g.checkpointPhase(source, sink, totalFlow)
 	}
 	
 	return totalFlow
@@ -859,7 +1040,7 @@ func (g *AdaptiveGraph) pushRelabelMaxFlow(source, sink int) int {
 	
 	// Main push-relabel loop with gap optimization
 	for {
-		activeNode := g.findActiveNode()
+		activeNode := g.findActiveNode(source, sink)
 		if activeNode == -1 {
 			break
 		}
@@ -1011,20 +1192,358 @@ func (g *AdaptiveGraph) relabel(node int) {
 }
 
 // findActiveNode finds a node with excess flow (excluding source and sink)
-func (g *AdaptiveGraph) findActiveNode() int {
-	for i := 1; i < g.vertices-1; i++ {
-		if g.Excess[i] > 0 {
+func (g *AdaptiveGraph) findActiveNode(source, sink int) int {
+	for i := 0; i < g.vertices; i++ {
+		if i != source && i != sink && g.Excess[i] > 0 {
 			return i
 		}
 	}
 	return -1
 }
 
+// ============================================================================
+// BOYKOV-KOLMOGOROV ALGORITHM (FOR GRID GRAPHS)
+// ============================================================================
+
+// Tree membership and parent markers for Boykov-Kolmogorov search trees
+const (
+	bkFree   int8 = 0
+	bkSource int8 = 1
+	bkSink   int8 = 2
+
+	bkTerminal = -1 // Parent marker for the tree roots
+	bkOrphan   = -2 // Parent marker for vertices cut off by saturation
+	bkNone     = -3 // Parent marker for free vertices
+)
+
+// bkState holds the two search trees reused across augmentations. parent[v]
+// is the index in AdjacencyList[v] of the edge leading to v's tree parent.
+type bkState struct {
+	tree     []int8
+	parent   []int
+	dist     []int // Distance to the root, valid when ts[v] is current
+	ts       []int // Timestamp of the last distance update
+	time     int
+	active   []int
+	isActive []bool
+	orphans  []int
+}
+
+// boykovKolmogorovMaxFlow grows source and sink search trees until they
+// touch, augments along the joining path and repairs the trees instead of
+// rebuilding them, which pays off on the short paths of grid graphs
+func (g *AdaptiveGraph) boykovKolmogorovMaxFlow(source, sink int) int {
+	bk := &bkState{
+		tree:     make([]int8, g.vertices),
+		parent:   make([]int, g.vertices),
+		dist:     make([]int, g.vertices),
+		ts:       make([]int, g.vertices),
+		isActive: make([]bool, g.vertices),
+	}
+	for i := range bk.parent {
+		bk.parent[i] = bkNone
+	}
+	
+	bk.tree[source], bk.parent[source] = bkSource, bkTerminal
+	bk.tree[sink], bk.parent[sink] = bkSink, bkTerminal
+	g.bkActivate(bk, source)
+	g.bkActivate(bk, sink)
+	
+	totalFlow := 0
+	for {
+		from, edgeIdx := g.bkGrow(bk)
+		if from == -1 {
+			break
+		}
+		
+		bk.time++
+		totalFlow += g.bkAugment(bk, from, edgeIdx)
+		g.bkAdopt(bk)
+		g.bfsIterations++
+		g.checkpointPhase(source, sink, totalFlow)
+	}
+	
+	return totalFlow
+}
+
+func (g *AdaptiveGraph) bkActivate(bk *bkState, v int) {
+	if !bk.isActive[v] {
+		bk.isActive[v] = true
+		bk.active = append(bk.active, v)
+	}
+}
+
+// bkResidualToChild returns residual capacity from v towards neighbour edge.To
+// in tree direction: away from the root in the source tree, towards it in
+// the sink tree
+func (g *AdaptiveGraph) bkResidualToChild(bk *bkState, v int, edge *Edge) int {
+	if bk.tree[v] == bkSource {
+		return edge.Capacity - edge.Flow
+	}
+	back := &g.AdjacencyList[edge.To][edge.Reverse]
+	return back.Capacity - back.Flow
+}
+
+// bkGrow expands active vertices until the trees meet and returns the
+// source-tree vertex and edge index of the joining edge, or -1 when done
+func (g *AdaptiveGraph) bkGrow(bk *bkState) (int, int) {
+	for len(bk.active) > 0 {
+		v := bk.active[0]
+		if bk.tree[v] == bkFree {
+			bk.active = bk.active[1:]
+			bk.isActive[v] = false
+			continue
+		}
+		
+		for i := range g.AdjacencyList[v] {
+			edge := &g.AdjacencyList[v][i]
+			if g.bkResidualToChild(bk, v, edge) <= 0 {
+				continue
+			}
+			
+			w := edge.To
+			switch {
+			case bk.tree[w] == bkFree:
+				bk.tree[w] = bk.tree[v]
+				bk.parent[w] = edge.Reverse
+				bk.dist[w] = bk.dist[v] + 1
+				bk.ts[w] = bk.ts[v]
+				g.bkActivate(bk, w)
+			case bk.tree[w] != bk.tree[v]:
+				// Trees touch; v stays active for the next growth stage
+				if bk.tree[v] == bkSource {
+					return v, i
+				}
+				return w, edge.Reverse
+			case bk.ts[w] <= bk.ts[v] && bk.dist[w] > bk.dist[v]:
+				// Shorter route to the root through v
+				bk.parent[w] = edge.Reverse
+				bk.dist[w] = bk.dist[v] + 1
+				bk.ts[w] = bk.ts[v]
+			}
+		}
+		
+		bk.active = bk.active[1:]
+		bk.isActive[v] = false
+		g.dfsIterations++
+	}
+	
+	return -1, -1
+}
+
+// bkAugment pushes the bottleneck along source-tree path, joining edge and
+// sink-tree path, turning vertices behind saturated tree edges into orphans
+func (g *AdaptiveGraph) bkAugment(bk *bkState, from, edgeIdx int) int {
+	bridge := &g.AdjacencyList[from][edgeIdx]
+	bottleneck := bridge.Capacity - bridge.Flow
+	
+	// Source side: edge parent->v is the reverse of v's parent edge
+	for v := from; bk.parent[v] != bkTerminal; {
+		up := &g.AdjacencyList[v][bk.parent[v]]
+		down := &g.AdjacencyList[up.To][up.Reverse]
+		bottleneck = min(bottleneck, down.Capacity-down.Flow)
+		v = up.To
+	}
+	// Sink side: edge v->parent is v's parent edge
+	for v := bridge.To; bk.parent[v] != bkTerminal; {
+		up := &g.AdjacencyList[v][bk.parent[v]]
+		bottleneck = min(bottleneck, up.Capacity-up.Flow)
+		v = up.To
+	}
+	
+	bridge.Flow += bottleneck
+	g.AdjacencyList[bridge.To][bridge.Reverse].Flow -= bottleneck
+	
+	for v := from; bk.parent[v] != bkTerminal; {
+		up := &g.AdjacencyList[v][bk.parent[v]]
+		down := &g.AdjacencyList[up.To][up.Reverse]
+		down.Flow += bottleneck
+		up.Flow -= bottleneck
+		next := up.To
+		if down.Capacity == down.Flow {
+			bk.parent[v] = bkOrphan
+			bk.orphans = append(bk.orphans, v)
+		}
+		v = next
+	}
+	for v := bridge.To; bk.parent[v] != bkTerminal; {
+		up := &g.AdjacencyList[v][bk.parent[v]]
+		up.Flow += bottleneck
+		g.AdjacencyList[up.To][up.Reverse].Flow -= bottleneck
+		next := up.To
+		if up.Capacity == up.Flow {
+			bk.parent[v] = bkOrphan
+			bk.orphans = append(bk.orphans, v)
+		}
+		v = next
+	}
+	
+	return bottleneck
+}
+
+// bkAdopt finds new parents for orphans or returns them to the free set
+func (g *AdaptiveGraph) bkAdopt(bk *bkState) {
+	for len(bk.orphans) > 0 {
+		o := bk.orphans[len(bk.orphans)-1]
+		bk.orphans = bk.orphans[:len(bk.orphans)-1]
+		
+		bestEdge, bestDist := -1, math.MaxInt32
+		for i := range g.AdjacencyList[o] {
+			edge := &g.AdjacencyList[o][i]
+			w := edge.To
+			if bk.tree[w] != bk.tree[o] || g.bkResidualFromParent(bk, o, edge) <= 0 {
+				continue
+			}
+			
+			d, valid := g.bkRootDistance(bk, w)
+			if valid && d+1 < bestDist {
+				bestEdge, bestDist = i, d+1
+			}
+		}
+		
+		if bestEdge != -1 {
+			bk.parent[o] = bestEdge
+			bk.dist[o] = bestDist
+			bk.ts[o] = bk.time
+			continue
+		}
+		
+		// No valid parent: free o and let neighbours in its tree regrow
+		for i := range g.AdjacencyList[o] {
+			edge := &g.AdjacencyList[o][i]
+			w := edge.To
+			if bk.tree[w] != bk.tree[o] {
+				continue
+			}
+			if g.bkResidualFromParent(bk, o, edge) > 0 {
+				g.bkActivate(bk, w)
+			}
+			if p := bk.parent[w]; p >= 0 && g.AdjacencyList[w][p].To == o {
+				bk.parent[w] = bkOrphan
+				bk.orphans = append(bk.orphans, w)
+			}
+		}
+		bk.tree[o] = bkFree
+		bk.parent[o] = bkNone
+	}
+}
+
+// bkResidualFromParent returns residual capacity of the tree edge that would
+// make edge.To the parent of v
+func (g *AdaptiveGraph) bkResidualFromParent(bk *bkState, v int, edge *Edge) int {
+	if bk.tree[v] == bkSource {
+		back := &g.AdjacencyList[edge.To][edge.Reverse]
+		return back.Capacity - back.Flow
+	}
+	return edge.Capacity - edge.Flow
+}
+
+// bkRootDistance walks from w to its tree root, returning the distance and
+// whether the path reaches a terminal; visited vertices get fresh timestamps
+func (g *AdaptiveGraph) bkRootDistance(bk *bkState, w int) (int, bool) {
+	d := 0
+	for v := w; ; {
+		if bk.ts[v] == bk.time {
+			d += bk.dist[v]
+			break
+		}
+		p := bk.parent[v]
+		if p == bkTerminal {
+			bk.ts[v] = bk.time
+			bk.dist[v] = 0
+			break
+		}
+		if p < 0 {
+			return 0, false // Origin lost through an orphan
+		}
+		d++
+		v = g.AdjacencyList[v][p].To
+	}
+	
+	// Cache distances along the walked path
+	for v, dv := w, d; bk.ts[v] != bk.time; dv-- {
+		bk.ts[v] = bk.time
+		bk.dist[v] = dv
+		v = g.AdjacencyList[v][bk.parent[v]].To
+	}
+	
+	return d, true
+}
+
+// GridConnectivity selects the pixel neighbourhood for SegmentGrid
+type GridConnectivity int
+
+const (
+	Grid4Connected GridConnectivity = 4
+	Grid8Connected GridConnectivity = 8
+)
+
+// SegmentGrid performs a binary graph-cut segmentation of a width x height
+// image. foreground[p] is paid when pixel p is labelled background and
+// background[p] when it is labelled foreground; neighbouring pixels with
+// different labels pay smoothness (scaled by 1/√2 on diagonals). It returns
+// the labelling (true for foreground) and the minimum energy.
+func SegmentGrid(width, height int, foreground, background []int, smoothness int, connectivity GridConnectivity) ([]bool, int) {
+	pixels := width * height
+	if width <= 0 || height <= 0 || len(foreground) != pixels || len(background) != pixels {
+		return nil, 0
+	}
+	
+	// Pixel (x, y) is vertex y*width+x, followed by the source and the sink
+	source, sink := pixels, pixels+1
+	g := NewAdaptiveGraph(pixels + 2)
+	
+	diagonal := int(float64(smoothness)/math.Sqrt2 + 0.5)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := y*width + x
+			if foreground[p] > 0 {
+				g.AddEdge(source, p, foreground[p])
+			}
+			if background[p] > 0 {
+				g.AddEdge(p, sink, background[p])
+			}
+			
+			if smoothness <= 0 {
+				continue
+			}
+			if x+1 < width {
+				g.AddEdge(p, p+1, smoothness)
+				g.AddEdge(p+1, p, smoothness)
+			}
+			if y+1 < height {
+				g.AddEdge(p, p+width, smoothness)
+				g.AddEdge(p+width, p, smoothness)
+			}
+			if connectivity == Grid8Connected && y+1 < height && diagonal > 0 {
+				if x+1 < width {
+					g.AddEdge(p, p+width+1, diagonal)
+					g.AddEdge(p+width+1, p, diagonal)
+				}
+				if x > 0 {
+					g.AddEdge(p, p+width-1, diagonal)
+					g.AddEdge(p+width-1, p, diagonal)
+				}
+			}
+		}
+	}
+	
+	g.algorithm = AlgoBoykovKolmogorov
+	g.graphType = GraphPlanar
+	energy := g.boykovKolmogorovMaxFlow(source, sink)
+	
+	labels := g.residualReachable(source)[:pixels:pixels]
+	return labels, energy
+}
+
 // ============================================================================
 // ADAPTIVE STRATEGY SELECTION
 // ============================================================================
 
-// MaxFlow automatically selects and executes optimal algorithm
+// MaxFlow automatically selects and executes optimal algorithm. With
+// ReductionEnabled, a source and sink joined by infinite-capacity edges
+// have no finite cut: MaxFlow returns INFINITE_CAPACITY, leaves every edge
+// flow at zero, and MinCut's source side then contains the sink.
 func (g *AdaptiveGraph) MaxFlow(source, sink int) int {
 	// Input validation
 	if source < 0 || source >= g.vertices || sink < 0 || sink >= g.vertices {
@@ -1033,6 +1552,15 @@ func (g *AdaptiveGraph) MaxFlow(source, sink int) int {
 	if source == sink {
 		return 0
 	}
+	g.lastSource, g.lastSink = source, sink
+	
+	// Optional reduction pass before any analysis
+	if g.ReductionEnabled {
+		if g.CheckpointPath != "" {
+			g.checkpointErr = errCheckpointReduction
+		}
+		return g.reducedMaxFlow(source, sink)
+	}
 	
 	// Fast path for small graphs - skip analysis overhead
 	if g.vertices < SMALL_GRAPH_THRESHOLD {
@@ -1054,6 +1582,8 @@ func (g *AdaptiveGraph) MaxFlow(source, sink int) int {
 		result = g.isapMaxFlow(source, sink)
 	case AlgoUnitCapacity:
 		result = g.unitCapacityMaxFlow(source, sink)
+	case AlgoBoykovKolmogorov:
+		result = g.boykovKolmogorovMaxFlow(source, sink)
 	default:
 		result = g.kyngDinicsMaxFlow(source, sink) // Default fallback
 	}
@@ -1062,6 +1592,37 @@ func (g *AdaptiveGraph) MaxFlow(source, sink int) int {
 	return result
 }
 
+// MinCut computes a maximum flow and returns its value together with the
+// source side of a minimum cut (true for vertices reachable from source)
+func (g *AdaptiveGraph) MinCut(source, sink int) (int, []bool) {
+	flow := g.MaxFlow(source, sink)
+	if source < 0 || source >= g.vertices {
+		return flow, make([]bool, g.vertices)
+	}
+	return flow, g.residualReachable(source)
+}
+
+// residualReachable marks vertices reachable from source in the residual graph
+func (g *AdaptiveGraph) residualReachable(source int) []bool {
+	reachable := make([]bool, g.vertices)
+	reachable[source] = true
+	
+	queue := []int{source}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		
+		for _, edge := range g.AdjacencyList[node] {
+			if !reachable[edge.To] && edge.Capacity > edge.Flow {
+				reachable[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	
+	return reachable
+}
+
 // ============================================================================
 // ADDITIONAL ALGORITHM IMPLEMENTATIONS
 // ============================================================================
@@ -1085,6 +1646,7 @@ func (g *AdaptiveGraph) standardDinicsMaxFlow(source, sink int) int {
 			totalFlow += flow
 		}
 		g.bfsIterations++
+		g.checkpointPhase(source, sink, totalFlow)
 	}
 	
 	return totalFlow
@@ -1299,33 +1861,8 @@ func (g *AdaptiveGraph) advanceCurrentVertex(node int) bool {
 
 // PrintStatistics displays algorithm performance metrics
 func (g *AdaptiveGraph) PrintStatistics() {
-	var algorithmName string
-	switch g.algorithm {
-	case AlgoStandardDinics:
-		algorithmName = "Standard Dinic's"
-	case AlgoKyngDinics:
-		algorithmName = "Kyng-Dinic's (Electrical Flow)"
-	case AlgoPushRelabel:
-		algorithmName = "Push-Relabel"
-	case AlgoISAP:
-		algorithmName = "ISAP"
-	case AlgoUnitCapacity:
-		algorithmName = "Unit Capacity Optimized"
-	}
-	
-	var graphTypeName string
-	switch g.graphType {
-	case GraphSmall:
-		graphTypeName = "Small"
-	case GraphSparse:
-		graphTypeName = "Sparse"
-	case GraphDense:
-		graphTypeName = "Dense"
-	case GraphUnitCapacity:
-		graphTypeName = "Unit Capacity"
-	case GraphPlanar:
-		graphTypeName = "Planar"
-	}
+	algorithmName := g.algorithm.String()
+	graphTypeName := g.graphType.String()
 	
 	fmt.Printf("=== ADAPTIVE KYNG-DINIC'S ALGORITHM STATISTICS ===\n")
 	fmt.Printf("Graph Type: %s (%d vertices, %d edges)\n", graphTypeName, g.vertices, g.edges)
@@ -1343,6 +1880,11 @@ func (g *AdaptiveGraph) PrintStatistics() {
 		fmt.Printf("Hybrid Strategy: %d concurrent + %d iterative paths\n", g.concurrentPaths, g.iterativePaths)
 	}
 	
+	// Show preprocessing effect when the reduction pass ran
+	if g.ReductionEnabled {
+		fmt.Printf("Reduced Graph: %d vertices, %d edges\n", g.reducedVertices, g.reducedEdges)
+	}
+	
 	// Show gap optimization statistics for Push-Relabel
 	if g.algorithm == AlgoPushRelabel && g.GapOptEnabled {
 		fmt.Printf("Gap Optimizations: %d\n", g.gapOptimizations)
@@ -1368,12 +1910,1839 @@ func max(a, b int) int {
 }
 
 // ============================================================================
-// LIBRARY INTERFACE - FOR TESTING USE 23B-adaptive-kyng-dinics-TEST.go
+// DISJOINT PATHS (UNIT-CAPACITY FLOW + FLOW DECOMPOSITION)
 // ============================================================================
 
-// This file provides the core algorithm implementation.
+// DisjointMode selects which resources the returned paths may not share
+type DisjointMode int
 
-func main() {
+const (
+	DisjointEdges    DisjointMode = iota // Paths may share vertices but not edges
+	DisjointVertices                     // Paths share only source and sink
+)
+
+// flowPath is one source-sink path of a flow decomposition
+type flowPath struct {
+	Vertices []int
+	Flow     int
+}
+
+// DisjointPaths returns up to k pairwise disjoint paths from source to sink.
+// Every edge with positive capacity counts as a single usable link; the
+// original graph is left untouched.
+func (g *AdaptiveGraph) DisjointPaths(source, sink, k int, mode DisjointMode) [][]int {
+	if source < 0 || source >= g.vertices || sink < 0 || sink >= g.vertices {
+		return nil
+	}
+	if source == sink || k <= 0 {
+		return nil
+	}
+
+	n := g.vertices
+	var unit *AdaptiveGraph
+	var superSource, unitSink int
+
+	switch mode {
+	case DisjointVertices:
+		// Node splitting: v_in = v, v_out = v+n, joined by a unit edge
+		unit = NewAdaptiveGraph(2*n + 1)
+		superSource = 2 * n
+		unitSink = sink
+		for v := 0; v < n; v++ {
+			if v == source || v == sink {
+				unit.AddEdge(v, v+n, k)
+			} else {
+				unit.AddEdge(v, v+n, 1)
+			}
+		}
+		for u := 0; u < n; u++ {
+			for _, edge := range g.AdjacencyList[u] {
+				if edge.Original && edge.Capacity > 0 {
+					unit.AddEdge(u+n, edge.To, 1)
+				}
+			}
+		}
+	default:
+		unit = NewAdaptiveGraph(n + 1)
+		superSource = n
+		unitSink = sink
+		for u := 0; u < n; u++ {
+			for _, edge := range g.AdjacencyList[u] {
+				if edge.Original && edge.Capacity > 0 {
+					unit.AddEdge(u, edge.To, 1)
+				}
+			}
+		}
+	}
+
+	// The super source caps the total flow at k paths
+	unit.AddEdge(superSource, source, k)
+	unit.algorithm = AlgoUnitCapacity
+	if unit.unitCapacityMaxFlow(superSource, unitSink) == 0 {
+		return nil
+	}
+
+	decomposed := unit.decomposeFlow(superSource, unitSink)
+	paths := make([][]int, 0, len(decomposed))
+	for _, fp := range decomposed {
+		path := make([]int, 0, len(fp.Vertices))
+		for _, v := range fp.Vertices {
+			if v == superSource {
+				continue
+			}
+			if v >= n {
+				v -= n // Out-copy of a split vertex
+			}
+			if len(path) > 0 && path[len(path)-1] == v {
+				continue
+			}
+			path = append(path, v)
+		}
+		for i := 0; i < fp.Flow; i++ {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// decomposeFlow splits the current Edge.Flow values into source-sink paths.
+// Flow around cycles is discarded; Edge.Flow itself is not modified.
+func (g *AdaptiveGraph) decomposeFlow(source, sink int) []flowPath {
+	remaining := make([][]int, g.vertices)
+	for v := range g.AdjacencyList {
+		remaining[v] = make([]int, len(g.AdjacencyList[v]))
+		for i, edge := range g.AdjacencyList[v] {
+			if edge.Original && edge.Flow > 0 {
+				remaining[v][i] = edge.Flow
+			}
+		}
+	}
+
+	// Position of each vertex on the path being walked, -1 when absent
+	onPath := make([]int, g.vertices)
+	for i := range onPath {
+		onPath[i] = -1
+	}
+
+	var paths []flowPath
+	for {
+		vertices := []int{source}
+		edgeIdx := []int{}
+		onPath[source] = 0
+		node := source
+
+		for node != sink {
+			next := -1
+			for i, flow := range remaining[node] {
+				if flow > 0 {
+					next = i
+					break
+				}
+			}
+			if next == -1 {
+				break
+			}
+
+			to := g.AdjacencyList[node][next].To
+			vertices = append(vertices, to)
+			edgeIdx = append(edgeIdx, next)
+
+			if pos := onPath[to]; pos != -1 {
+				// Cancel the cycle and resume from its entry vertex
+				cycleFlow := math.MaxInt32
+				for j := pos; j < len(edgeIdx); j++ {
+					cycleFlow = min(cycleFlow, remaining[vertices[j]][edgeIdx[j]])
+				}
+				for j := pos; j < len(edgeIdx); j++ {
+					remaining[vertices[j]][edgeIdx[j]] -= cycleFlow
+					if j > pos {
+						onPath[vertices[j]] = -1
+					}
+				}
+				vertices = vertices[:pos+1]
+				edgeIdx = edgeIdx[:pos]
+				node = to
+				continue
+			}
+
+			onPath[to] = len(vertices) - 1
+			node = to
+		}
+
+		for _, v := range vertices {
+			onPath[v] = -1
+		}
+		if node != sink {
+			break
+		}
+
+		bottleneck := math.MaxInt32
+		for j, idx := range edgeIdx {
+			bottleneck = min(bottleneck, remaining[vertices[j]][idx])
+		}
+		for j, idx := range edgeIdx {
+			remaining[vertices[j]][idx] -= bottleneck
+		}
+		paths = append(paths, flowPath{Vertices: vertices, Flow: bottleneck})
+	}
+
+	return paths
+}
+
+// ============================================================================
+// ASSIGNMENT PROBLEM (MIN-COST BIPARTITE MATCHING)
+// ============================================================================
+
+// assignInf is the "no edge" sentinel for cost-based searches
+const assignInf = math.MaxInt64 / 4
+
+// AssignmentEdge is one allowed row-column pairing for AssignSparse
+type AssignmentEdge struct {
+	Row, Col, Cost int
+}
+
+// Assign solves the dense assignment problem with the Hungarian algorithm.
+// It returns the column chosen for each row (-1 when a rectangular matrix
+// has more rows than columns) and the total cost of the assignment.
+func Assign(costMatrix [][]int) ([]int, int) {
+	rows := len(costMatrix)
+	if rows == 0 {
+		return []int{}, 0
+	}
+	cols := len(costMatrix[0])
+	for _, row := range costMatrix {
+		if len(row) != cols {
+			return nil, 0 // Ragged matrix
+		}
+	}
+	if cols == 0 {
+		return fillAssignment(rows), 0
+	}
+
+	// Hungarian needs rows <= cols; solve the transpose otherwise
+	if rows > cols {
+		transposed := make([][]int, cols)
+		for j := range transposed {
+			transposed[j] = make([]int, rows)
+			for i := 0; i < rows; i++ {
+				transposed[j][i] = costMatrix[i][j]
+			}
+		}
+		colToRow := hungarian(transposed)
+		rowToCol := fillAssignment(rows)
+		for j, i := range colToRow {
+			rowToCol[i] = j
+		}
+		return rowToCol, assignmentCost(costMatrix, rowToCol)
+	}
+
+	rowToCol := hungarian(costMatrix)
+	return rowToCol, assignmentCost(costMatrix, rowToCol)
+}
+
+// hungarian runs the O(n²m) potentials method on a matrix with rows <= cols
+func hungarian(cost [][]int) []int {
+	n, m := len(cost), len(cost[0])
+
+	// 1-indexed potentials; column 0 is a virtual column for the current row
+	u := make([]int, n+1)
+	v := make([]int, m+1)
+	match := make([]int, m+1) // Row matched to each column
+	way := make([]int, m+1)   // Previous column on the alternating path
+	minv := make([]int, m+1)
+	used := make([]bool, m+1)
+
+	for i := 1; i <= n; i++ {
+		match[0] = i
+		j0 := 0
+		for j := range minv {
+			minv[j] = assignInf
+			used[j] = false
+		}
+
+		for {
+			used[j0] = true
+			i0 := match[j0]
+			delta := assignInf
+			j1 := 0
+
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				reduced := cost[i0-1][j-1] - u[i0] - v[j]
+				if reduced < minv[j] {
+					minv[j] = reduced
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[match[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+
+			j0 = j1
+			if match[j0] == 0 {
+				break
+			}
+		}
+
+		// Flip the alternating path back to the virtual column
+		for j0 != 0 {
+			j1 := way[j0]
+			match[j0] = match[j1]
+			j0 = j1
+		}
+	}
+
+	rowToCol := fillAssignment(n)
+	for j := 1; j <= m; j++ {
+		if match[j] != 0 {
+			rowToCol[match[j]-1] = j - 1
+		}
+	}
+	return rowToCol
+}
+
+// AssignSparse solves the assignment problem over an explicit edge list using
+// min-cost flow on the AdaptiveGraph residual structure. It matches as many
+// rows as possible and, among those matchings, returns one of minimum cost.
+func AssignSparse(rows, cols int, edges []AssignmentEdge) ([]int, int) {
+	source := rows + cols
+	sink := source + 1
+	g := NewAdaptiveGraph(rows + cols + 2)
+
+	for i := 0; i < rows; i++ {
+		g.AddEdgeWithCost(source, i, 1, 0)
+	}
+	for j := 0; j < cols; j++ {
+		g.AddEdgeWithCost(rows+j, sink, 1, 0)
+	}
+	for _, e := range edges {
+		if e.Row < 0 || e.Row >= rows || e.Col < 0 || e.Col >= cols {
+			continue
+		}
+		g.AddEdgeWithCost(e.Row, rows+e.Col, 1, e.Cost)
+	}
+
+	_, totalCost := g.minCostMaxFlow(source, sink)
+
+	rowToCol := fillAssignment(rows)
+	for i := 0; i < rows; i++ {
+		for _, edge := range g.AdjacencyList[i] {
+			if edge.Original && edge.Flow > 0 {
+				rowToCol[i] = edge.To - rows
+				break
+			}
+		}
+	}
+	return rowToCol, totalCost
+}
+
+// minCostMaxFlow runs successive shortest paths with Johnson potentials and
+// returns the maximum flow value together with its minimum total cost
+func (g *AdaptiveGraph) minCostMaxFlow(source, sink int) (int, int) {
+	if g.EdgeCost == nil {
+		g.EdgeCost = make([][]int, g.vertices)
+		for v := range g.AdjacencyList {
+			g.EdgeCost[v] = make([]int, len(g.AdjacencyList[v]))
+		}
+	}
+
+	potential := g.bellmanFordPotentials(source)
+	dist := make([]int, g.vertices)
+	prevVertex := make([]int, g.vertices)
+	prevEdge := make([]int, g.vertices)
+	totalFlow, totalCost := 0, 0
+
+	for {
+		// Dijkstra on reduced costs, which are non-negative under the potentials
+		for i := range dist {
+			dist[i] = assignInf
+			prevVertex[i] = -1
+		}
+		dist[source] = 0
+		pq := &costQueue{{vertex: source, dist: 0}}
+
+		for pq.Len() > 0 {
+			item := heap.Pop(pq).(costItem)
+			if item.dist > dist[item.vertex] {
+				continue
+			}
+			node := item.vertex
+			for i, edge := range g.AdjacencyList[node] {
+				if edge.Capacity <= edge.Flow || potential[edge.To] == assignInf {
+					continue
+				}
+				next := dist[node] + g.EdgeCost[node][i] + potential[node] - potential[edge.To]
+				if next < dist[edge.To] {
+					dist[edge.To] = next
+					prevVertex[edge.To] = node
+					prevEdge[edge.To] = i
+					heap.Push(pq, costItem{vertex: edge.To, dist: next})
+				}
+			}
+		}
+
+		if dist[sink] == assignInf {
+			break
+		}
+		for v := range potential {
+			if dist[v] < assignInf {
+				potential[v] += dist[v]
+			}
+		}
+
+		// Bottleneck along the shortest path
+		pushed := math.MaxInt32
+		for v := sink; v != source; v = prevVertex[v] {
+			edge := &g.AdjacencyList[prevVertex[v]][prevEdge[v]]
+			pushed = min(pushed, edge.Capacity-edge.Flow)
+		}
+		for v := sink; v != source; v = prevVertex[v] {
+			u := prevVertex[v]
+			edge := &g.AdjacencyList[u][prevEdge[v]]
+			edge.Flow += pushed
+			g.AdjacencyList[edge.To][edge.Reverse].Flow -= pushed
+			totalCost += pushed * g.EdgeCost[u][prevEdge[v]]
+		}
+		totalFlow += pushed
+		g.bfsIterations++
+	}
+
+	return totalFlow, totalCost
+}
+
+// bellmanFordPotentials computes initial shortest-path potentials so that
+// negative edge costs are allowed; unreachable vertices stay at assignInf
+func (g *AdaptiveGraph) bellmanFordPotentials(source int) []int {
+	potential := make([]int, g.vertices)
+	for i := range potential {
+		potential[i] = assignInf
+	}
+	potential[source] = 0
+
+	for iter := 0; iter < g.vertices; iter++ {
+		changed := false
+		for u := 0; u < g.vertices; u++ {
+			if potential[u] == assignInf {
+				continue
+			}
+			for i, edge := range g.AdjacencyList[u] {
+				if edge.Capacity > edge.Flow && potential[u]+g.EdgeCost[u][i] < potential[edge.To] {
+					potential[edge.To] = potential[u] + g.EdgeCost[u][i]
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	return potential
+}
+
+// costItem and costQueue implement container/heap for the Dijkstra phase
+type costItem struct {
+	vertex, dist int
+}
+
+type costQueue []costItem
+
+func (q costQueue) Len() int            { return len(q) }
+func (q costQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q costQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *costQueue) Push(x interface{}) { *q = append(*q, x.(costItem)) }
+func (q *costQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+func fillAssignment(n int) []int {
+	assignment := make([]int, n)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	return assignment
+}
+
+func assignmentCost(costMatrix [][]int, rowToCol []int) int {
+	total := 0
+	for i, j := range rowToCol {
+		if j >= 0 {
+			total += costMatrix[i][j]
+		}
+	}
+	return total
+}
+
+// ============================================================================
+// MAXIMUM-WEIGHT CLOSURE (PROJECT SELECTION)
+// ============================================================================
+
+// MaxClosure solves the project-selection problem. weights[i] is the profit
+// (positive) or cost (negative) of item i, and each dependency {a, b} means
+// choosing a requires choosing b. It returns the chosen items in ascending
+// order and their total weight.
+func MaxClosure(weights []int, deps [][2]int) ([]int, int) {
+	n := len(weights)
+
+	// Item i is vertex i, followed by the source and the sink
+	source, sink := n, n+1
+	g := NewAdaptiveGraph(n + 2)
+
+	positiveTotal := 0
+	for i, w := range weights {
+		if w > 0 {
+			g.AddEdge(source, i, w)
+			positiveTotal += w
+		} else if w < 0 {
+			g.AddEdge(i, sink, -w)
+		}
+	}
+
+	// Dependency edges must never be cut; the total profit bounds any cut
+	infinite := positiveTotal + 1
+	for _, dep := range deps {
+		a, b := dep[0], dep[1]
+		if a < 0 || a >= n || b < 0 || b >= n || a == b {
+			continue
+		}
+		g.AddEdge(a, b, infinite)
+	}
+
+	cut, sourceSide := g.MinCut(source, sink)
+
+	chosen := []int{}
+	for i := 0; i < n; i++ {
+		if sourceSide[i] {
+			chosen = append(chosen, i)
+		}
+	}
+	return chosen, positiveTotal - cut
+}
+
+// ============================================================================
+// GRAPH PREPROCESSING AND REDUCTION
+// ============================================================================
+
+// Reduced edge kinds: one original edge, or a series/parallel bundle
+const (
+	reduceLeaf = iota
+	reduceSeries
+	reduceParallel
+)
+
+// reduceEdge is an edge of the reduced multigraph between infinite-capacity
+// components. Bundles keep their parts so a flow on the reduced edge can be
+// split back onto the original edges.
+type reduceEdge struct {
+	from, to int
+	capacity int
+	kind     int
+	vertex   int // Leaf only: tail vertex of the original edge
+	index    int // Leaf only: position in AdjacencyList[vertex]
+	parts    []*reduceEdge
+	dead     bool
+}
+
+// graphReduction is the solved-on representation of an AdaptiveGraph
+type graphReduction struct {
+	reduced      *AdaptiveGraph
+	source, sink int
+	component    []int // Infinite-capacity SCC of every original vertex
+	components   int
+	edges        []*reduceEdge
+	compact      []int // Component -> reduced vertex ID
+	edgeIndex    []int // Position of each reduced edge in its tail's adjacency
+}
+
+// reducedMaxFlow shrinks the graph, solves the reduced instance with the
+// adaptive engine and writes an equivalent flow back onto the original edges
+func (g *AdaptiveGraph) reducedMaxFlow(source, sink int) int {
+	start := time.Now()
+	
+	// Flows from an earlier solve must not leak into cuts or exports
+	for v := range g.AdjacencyList {
+		for i := range g.AdjacencyList[v] {
+			g.AdjacencyList[v][i].Flow = 0
+		}
+	}
+	
+	r := g.reduce(source, sink)
+	if r == nil {
+		// Source and sink share an infinite-capacity component: no finite
+		// cut exists, so report the sentinel with every flow left at zero
+		g.reducedVertices, g.reducedEdges = 0, 0
+		g.totalComputeTime = time.Since(start)
+		return INFINITE_CAPACITY
+	}
+	g.reducedVertices = r.reduced.vertices
+	g.reducedEdges = r.reduced.edges
+	
+	flow := r.reduced.MaxFlow(r.source, r.sink)
+	
+	// Map the reduced flow back onto original IDs
+	for i, e := range r.edges {
+		reducedFlow := r.reduced.AdjacencyList[r.compact[e.from]][r.edgeIndex[i]].Flow
+		if reducedFlow > 0 {
+			g.expandReducedFlow(e, reducedFlow)
+		}
+	}
+	g.routeComponentFlow(r.component, r.components, source, sink)
+	
+	// Report the solver the reduced graph ended up with
+	g.algorithm = r.reduced.algorithm
+	g.graphType = r.reduced.graphType
+	g.bfsIterations += r.reduced.bfsIterations
+	g.dfsIterations += r.reduced.dfsIterations
+	g.gapOptimizations += r.reduced.gapOptimizations
+	g.concurrentPaths += r.reduced.concurrentPaths
+	g.iterativePaths += r.reduced.iterativePaths
+	g.totalComputeTime = time.Since(start)
+	
+	return flow
+}
+
+// reduce collapses infinite-capacity SCCs, drops edges off every s-t path,
+// merges parallel edges and contracts degree-2 chains until nothing changes
+func (g *AdaptiveGraph) reduce(source, sink int) *graphReduction {
+	component, count := g.infiniteComponents()
+	s, t := component[source], component[sink]
+	if s == t {
+		return nil
+	}
+	
+	var edges []*reduceEdge
+	for u := range g.AdjacencyList {
+		for i, edge := range g.AdjacencyList[u] {
+			if !edge.Original || edge.Capacity <= 0 || component[u] == component[edge.To] {
+				continue
+			}
+			edges = append(edges, &reduceEdge{
+				from:     component[u],
+				to:       component[edge.To],
+				capacity: edge.Capacity,
+				kind:     reduceLeaf,
+				vertex:   u,
+				index:    i,
+			})
+		}
+	}
+	
+	edges = pruneReduceEdges(edges, count, s, t)
+	for {
+		edges = mergeParallelEdges(edges)
+		var contracted bool
+		edges, contracted = contractChains(edges, count, s, t)
+		if !contracted {
+			break
+		}
+	}
+	
+	// Number the surviving components; the terminals stay even when every
+	// edge touching them was pruned
+	compact := make([]int, count)
+	for i := range compact {
+		compact[i] = -1
+	}
+	next := 0
+	for _, e := range edges {
+		for _, v := range [2]int{e.from, e.to} {
+			if compact[v] == -1 {
+				compact[v] = next
+				next++
+			}
+		}
+	}
+	for _, v := range [2]int{s, t} {
+		if compact[v] == -1 {
+			compact[v] = next
+			next++
+		}
+	}
+	
+	reduced := NewAdaptiveGraph(next)
+	edgeIndex := make([]int, len(edges))
+	for i, e := range edges {
+		edgeIndex[i] = len(reduced.AdjacencyList[compact[e.from]])
+		reduced.AddEdge(compact[e.from], compact[e.to], e.capacity)
+	}
+	
+	return &graphReduction{
+		reduced:    reduced,
+		source:     compact[s],
+		sink:       compact[t],
+		component:  component,
+		components: count,
+		edges:      edges,
+		compact:    compact,
+		edgeIndex:  edgeIndex,
+	}
+}
+
+// infiniteComponents labels the strongly connected components formed by
+// infinite-capacity edges using an iterative Tarjan search
+func (g *AdaptiveGraph) infiniteComponents() ([]int, int) {
+	n := g.vertices
+	component := make([]int, n)
+	index := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+	
+	type tarjanFrame struct {
+		vertex, edge int
+	}
+	var stack []int
+	count, counter := 0, 0
+	
+	for root := 0; root < n; root++ {
+		if index[root] != -1 {
+			continue
+		}
+		index[root], low[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+		calls := []tarjanFrame{{vertex: root}}
+		
+		for len(calls) > 0 {
+			frame := &calls[len(calls)-1]
+			v := frame.vertex
+			
+			if frame.edge < len(g.AdjacencyList[v]) {
+				edge := g.AdjacencyList[v][frame.edge]
+				frame.edge++
+				if !edge.Original || edge.Capacity < INFINITE_CAPACITY {
+					continue
+				}
+				w := edge.To
+				if index[w] == -1 {
+					index[w], low[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, tarjanFrame{vertex: w})
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+			
+			// All edges of v explored: close its component if it is a root
+			if low[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component[w] = count
+					if w == v {
+						break
+					}
+				}
+				count++
+			}
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].vertex
+				low[parent] = min(low[parent], low[v])
+			}
+		}
+	}
+	
+	return component, count
+}
+
+// pruneReduceEdges keeps only edges u->v with u reachable from s and t
+// reachable from v; everything else can never carry s-t flow
+func pruneReduceEdges(edges []*reduceEdge, count, s, t int) []*reduceEdge {
+	out := make([][]int, count)
+	in := make([][]int, count)
+	for _, e := range edges {
+		out[e.from] = append(out[e.from], e.to)
+		in[e.to] = append(in[e.to], e.from)
+	}
+	
+	fromSource := reachableVertices(out, s)
+	toSink := reachableVertices(in, t)
+	
+	kept := edges[:0]
+	for _, e := range edges {
+		if fromSource[e.from] && toSink[e.to] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func reachableVertices(adjacency [][]int, start int) []bool {
+	reached := make([]bool, len(adjacency))
+	reached[start] = true
+	queue := []int{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[node] {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reached
+}
+
+// mergeParallelEdges bundles edges with the same endpoints into one edge
+// carrying the summed capacity
+func mergeParallelEdges(edges []*reduceEdge) []*reduceEdge {
+	groups := make(map[[2]int][]*reduceEdge, len(edges))
+	var order [][2]int
+	for _, e := range edges {
+		key := [2]int{e.from, e.to}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], e)
+	}
+	
+	merged := make([]*reduceEdge, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		if len(group) == 1 {
+			merged = append(merged, group[0])
+			continue
+		}
+		
+		bundle := &reduceEdge{from: key[0], to: key[1], kind: reduceParallel}
+		for _, e := range group {
+			bundle.capacity += e.capacity
+			if e.kind == reduceParallel {
+				bundle.parts = append(bundle.parts, e.parts...)
+			} else {
+				bundle.parts = append(bundle.parts, e)
+			}
+		}
+		merged = append(merged, bundle)
+	}
+	return merged
+}
+
+// contractChains replaces u->x->v through a vertex with exactly one in-edge
+// and one out-edge by a single bottleneck edge u->v
+func contractChains(edges []*reduceEdge, count, s, t int) ([]*reduceEdge, bool) {
+	in := make([][]*reduceEdge, count)
+	out := make([][]*reduceEdge, count)
+	for _, e := range edges {
+		out[e.from] = append(out[e.from], e)
+		in[e.to] = append(in[e.to], e)
+	}
+	
+	contracted := false
+	var chains []*reduceEdge
+	for x := 0; x < count; x++ {
+		if x == s || x == t {
+			continue
+		}
+		in[x] = liveReduceEdges(in[x])
+		out[x] = liveReduceEdges(out[x])
+		if len(in[x]) != 1 || len(out[x]) != 1 {
+			continue
+		}
+		
+		first, second := in[x][0], out[x][0]
+		first.dead, second.dead = true, true
+		contracted = true
+		if first.from == second.to {
+			continue // u->x->u only circulates flow
+		}
+		
+		chain := &reduceEdge{
+			from:     first.from,
+			to:       second.to,
+			capacity: min(first.capacity, second.capacity),
+			kind:     reduceSeries,
+		}
+		for _, e := range [2]*reduceEdge{first, second} {
+			if e.kind == reduceSeries {
+				chain.parts = append(chain.parts, e.parts...)
+			} else {
+				chain.parts = append(chain.parts, e)
+			}
+		}
+		out[chain.from] = append(out[chain.from], chain)
+		in[chain.to] = append(in[chain.to], chain)
+		chains = append(chains, chain)
+	}
+	
+	return liveReduceEdges(append(edges, chains...)), contracted
+}
+
+func liveReduceEdges(edges []*reduceEdge) []*reduceEdge {
+	live := edges[:0]
+	for _, e := range edges {
+		if !e.dead {
+			live = append(live, e)
+		}
+	}
+	return live
+}
+
+// expandReducedFlow writes a reduced-edge flow onto the original edges:
+// series parts all carry it, parallel parts are filled one after another
+func (g *AdaptiveGraph) expandReducedFlow(e *reduceEdge, flow int) {
+	switch e.kind {
+	case reduceLeaf:
+		edge := &g.AdjacencyList[e.vertex][e.index]
+		edge.Flow += flow
+		g.AdjacencyList[edge.To][edge.Reverse].Flow -= flow
+	case reduceSeries:
+		for _, part := range e.parts {
+			g.expandReducedFlow(part, flow)
+		}
+	case reduceParallel:
+		remaining := flow
+		for _, part := range e.parts {
+			share := min(remaining, part.capacity)
+			if share > 0 {
+				g.expandReducedFlow(part, share)
+				remaining -= share
+			}
+		}
+	}
+}
+
+// routeComponentFlow restores conservation inside collapsed components by
+// routing each member's imbalance to or from a root over infinite edges
+func (g *AdaptiveGraph) routeComponentFlow(component []int, count, source, sink int) {
+	size := make([]int, count)
+	for _, c := range component {
+		size[c]++
+	}
+	
+	members := make(map[int][]int)
+	for v, c := range component {
+		if size[c] > 1 {
+			members[c] = append(members[c], v)
+		}
+	}
+	if len(members) == 0 {
+		return
+	}
+	
+	imbalance := make([]int, g.vertices)
+	for u := range g.AdjacencyList {
+		for _, edge := range g.AdjacencyList[u] {
+			if edge.Original && edge.Flow != 0 {
+				imbalance[u] -= edge.Flow
+				imbalance[edge.To] += edge.Flow
+			}
+		}
+	}
+	
+	// BFS trees over infinite edges: towards the root and away from it
+	toRoot := make([]int, g.vertices)      // Edge index in AdjacencyList[v] one hop closer
+	fromRoot := make([]int, g.vertices)    // Predecessor on the root->v path
+	fromRootIdx := make([]int, g.vertices) // Edge index in AdjacencyList[fromRoot[v]]
+	
+	for c, group := range members {
+		root := group[0]
+		if c == component[source] {
+			root = source
+		} else if c == component[sink] {
+			root = sink
+		}
+		
+		for _, v := range group {
+			toRoot[v], fromRoot[v] = -1, -1
+		}
+		queue := []int{root}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			for _, edge := range g.AdjacencyList[node] {
+				if edge.Original || component[edge.To] != c || edge.To == root || toRoot[edge.To] != -1 {
+					continue
+				}
+				forward := g.AdjacencyList[edge.To][edge.Reverse]
+				if forward.Capacity >= INFINITE_CAPACITY {
+					toRoot[edge.To] = edge.Reverse
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+		queue = append(queue[:0], root)
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			for i, edge := range g.AdjacencyList[node] {
+				if !edge.Original || edge.Capacity < INFINITE_CAPACITY || component[edge.To] != c || edge.To == root || fromRoot[edge.To] != -1 {
+					continue
+				}
+				fromRoot[edge.To], fromRootIdx[edge.To] = node, i
+				queue = append(queue, edge.To)
+			}
+		}
+		
+		for _, v := range group {
+			if v == root || v == source || v == sink {
+				continue
+			}
+			if amount := imbalance[v]; amount > 0 {
+				for x := v; x != root; {
+					edge := &g.AdjacencyList[x][toRoot[x]]
+					edge.Flow += amount
+					g.AdjacencyList[edge.To][edge.Reverse].Flow -= amount
+					x = edge.To
+				}
+			} else if amount < 0 {
+				for x := v; x != root; x = fromRoot[x] {
+					edge := &g.AdjacencyList[fromRoot[x]][fromRootIdx[x]]
+					edge.Flow -= amount
+					g.AdjacencyList[edge.To][edge.Reverse].Flow += amount
+				}
+			}
+		}
+	}
+}
+
+// ============================================================================
+// CHECKPOINT AND RESUME
+// ============================================================================
+
+// Snapshot layout: little-endian checkpointHeader, one int64 Flow per
+// adjacency entry in vertex order, then a CRC-32C of everything before it
+const checkpointVersion = 1
+
+var (
+	checkpointMagic = [4]byte{'K', 'D', 'C', 'P'}
+	checkpointTable = crc32.MakeTable(crc32.Castagnoli)
+	
+	// errCheckpointReduction is reported when ReductionEnabled and
+	// CheckpointPath are both set: snapshots would describe the reduced
+	// graph, which a resume on the original graph cannot rebuild
+	errCheckpointReduction = errors.New("checkpoint: not supported with ReductionEnabled")
+)
+
+type checkpointHeader struct {
+	Magic            [4]byte
+	Version          uint32
+	Vertices         uint64
+	Edges            uint64
+	Entries          uint64 // Adjacency entries (original + reverse)
+	StructureHash    uint64
+	Source, Sink     uint64
+	Algorithm        uint32
+	GraphType        uint32
+	TotalFlow        int64
+	BFSIterations    uint64
+	DFSIterations    uint64
+	GapOptimizations uint64
+	ConcurrentPaths  int64
+	IterativePaths   int64
+}
+
+// CheckpointError reports the last failure to write a checkpoint, if any
+func (g *AdaptiveGraph) CheckpointError() error {
+	return g.checkpointErr
+}
+
+// checkpointPhase writes a snapshot after every CheckpointPhases phases
+func (g *AdaptiveGraph) checkpointPhase(source, sink, phaseFlow int) {
+	if g.CheckpointPath == "" {
+		return
+	}
+	interval := max(1, g.CheckpointPhases)
+	if g.bfsIterations%interval != 0 {
+		return
+	}
+	if err := g.writeCheckpoint(source, sink, g.checkpointBase+phaseFlow); err != nil {
+		g.checkpointErr = err
+	}
+}
+
+// writeCheckpoint stores the current flow state atomically (temp file + rename)
+func (g *AdaptiveGraph) writeCheckpoint(source, sink, totalFlow int) error {
+	if g.checkpointHash == 0 {
+		g.checkpointHash = g.structureHash()
+	}
+	
+	header := checkpointHeader{
+		Magic:            checkpointMagic,
+		Version:          checkpointVersion,
+		Vertices:         uint64(g.vertices),
+		Edges:            uint64(g.edges),
+		Entries:          uint64(g.adjacencyEntries()),
+		StructureHash:    g.checkpointHash,
+		Source:           uint64(source),
+		Sink:             uint64(sink),
+		Algorithm:        uint32(g.algorithm),
+		GraphType:        uint32(g.graphType),
+		TotalFlow:        int64(totalFlow),
+		BFSIterations:    uint64(g.bfsIterations),
+		DFSIterations:    uint64(g.dfsIterations),
+		GapOptimizations: uint64(g.gapOptimizations),
+		ConcurrentPaths:  g.concurrentPaths,
+		IterativePaths:   g.iterativePaths,
+	}
+	
+	tmpPath := g.CheckpointPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	
+	buffered := bufio.NewWriterSize(file, 1<<20)
+	checksum := crc32.New(checkpointTable)
+	out := io.MultiWriter(buffered, checksum)
+	
+	err = binary.Write(out, binary.LittleEndian, &header)
+	var entry [8]byte
+	for v := 0; v < g.vertices && err == nil; v++ {
+		for i := range g.AdjacencyList[v] {
+			binary.LittleEndian.PutUint64(entry[:], uint64(int64(g.AdjacencyList[v][i].Flow)))
+			if _, err = out.Write(entry[:]); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = binary.Write(buffered, binary.LittleEndian, checksum.Sum32())
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, g.CheckpointPath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	return nil
+}
+
+// ResumeMaxFlow restores a snapshot written by an interrupted MaxFlow on the
+// same graph and finishes the computation with the algorithm recorded in it.
+// The returned flow includes the flow restored from the snapshot.
+func (g *AdaptiveGraph) ResumeMaxFlow(checkpointPath string) (int, error) {
+	if g.ReductionEnabled {
+		return 0, errCheckpointReduction
+	}
+	header, err := g.loadCheckpoint(checkpointPath)
+	if err != nil {
+		return 0, err
+	}
+	
+	start := time.Now()
+	source, sink := int(header.Source), int(header.Sink)
+	g.lastSource, g.lastSink = source, sink
+	if g.CheckpointPath == "" {
+		g.CheckpointPath = checkpointPath
+	}
+	g.checkpointBase = int(header.TotalFlow)
+	defer func() { g.checkpointBase = 0 }()
+	
+	var remaining int
+	switch g.algorithm {
+	case AlgoStandardDinics:
+		remaining = g.standardDinicsMaxFlow(source, sink)
+	case AlgoKyngDinics:
+		remaining = g.kyngDinicsMaxFlow(source, sink)
+	case AlgoUnitCapacity:
+		remaining = g.unitCapacityMaxFlow(source, sink)
+	case AlgoBoykovKolmogorov:
+		remaining = g.boykovKolmogorovMaxFlow(source, sink)
+	default:
+		return 0, fmt.Errorf("checkpoint: algorithm %d cannot resume", g.algorithm)
+	}
+	
+	g.totalComputeTime = time.Since(start)
+	return int(header.TotalFlow) + remaining, nil
+}
+
+// loadCheckpoint validates a snapshot against this graph and restores flows
+// and counters; on a checksum mismatch all flows are reset to zero
+func (g *AdaptiveGraph) loadCheckpoint(path string) (checkpointHeader, error) {
+	var header checkpointHeader
+	
+	file, err := os.Open(path)
+	if err != nil {
+		return header, fmt.Errorf("checkpoint: %w", err)
+	}
+	defer file.Close()
+	
+	buffered := bufio.NewReaderSize(file, 1<<20)
+	checksum := crc32.New(checkpointTable)
+	in := io.TeeReader(buffered, checksum)
+	
+	if err := binary.Read(in, binary.LittleEndian, &header); err != nil {
+		return header, fmt.Errorf("checkpoint: reading header: %w", err)
+	}
+	switch {
+	case header.Magic != checkpointMagic:
+		return header, errors.New("checkpoint: not a max-flow checkpoint")
+	case header.Version != checkpointVersion:
+		return header, fmt.Errorf("checkpoint: unsupported version %d", header.Version)
+	case header.Vertices != uint64(g.vertices) || header.Edges != uint64(g.edges) ||
+		header.Entries != uint64(g.adjacencyEntries()):
+		return header, errors.New("checkpoint: graph size does not match snapshot")
+	case header.Source >= header.Vertices || header.Sink >= header.Vertices:
+		return header, errors.New("checkpoint: terminals out of range")
+	}
+	if g.checkpointHash == 0 {
+		g.checkpointHash = g.structureHash()
+	}
+	if header.StructureHash != g.checkpointHash {
+		return header, errors.New("checkpoint: graph structure does not match snapshot")
+	}
+	
+	var entry [8]byte
+	for v := 0; v < g.vertices && err == nil; v++ {
+		for i := range g.AdjacencyList[v] {
+			if _, err = io.ReadFull(in, entry[:]); err != nil {
+				break
+			}
+			g.AdjacencyList[v][i].Flow = int(int64(binary.LittleEndian.Uint64(entry[:])))
+		}
+	}
+	var stored uint32
+	if err == nil {
+		err = binary.Read(buffered, binary.LittleEndian, &stored)
+	}
+	if err != nil || stored != checksum.Sum32() {
+		for v := range g.AdjacencyList {
+			for i := range g.AdjacencyList[v] {
+				g.AdjacencyList[v][i].Flow = 0
+			}
+		}
+		if err != nil {
+			return header, fmt.Errorf("checkpoint: reading flows: %w", err)
+		}
+		return header, errors.New("checkpoint: checksum mismatch, snapshot is corrupt")
+	}
+	
+	g.algorithm = FlowAlgorithm(header.Algorithm)
+	g.graphType = GraphType(header.GraphType)
+	g.bfsIterations = int(header.BFSIterations)
+	g.dfsIterations = int(header.DFSIterations)
+	g.gapOptimizations = int(header.GapOptimizations)
+	g.concurrentPaths = header.ConcurrentPaths
+	g.iterativePaths = header.IterativePaths
+	
+	return header, nil
+}
+
+// structureHash fingerprints edge endpoints and capacities (FNV-1a)
+func (g *AdaptiveGraph) structureHash() uint64 {
+	h := fnv.New64a()
+	var word [8]byte
+	for v := 0; v < g.vertices; v++ {
+		for _, edge := range g.AdjacencyList[v] {
+			binary.LittleEndian.PutUint64(word[:], uint64(edge.To))
+			h.Write(word[:])
+			binary.LittleEndian.PutUint64(word[:], uint64(edge.Capacity))
+			h.Write(word[:])
+		}
+	}
+	return h.Sum64() | 1 // Never zero, zero means "not computed"
+}
+
+func (g *AdaptiveGraph) adjacencyEntries() int {
+	entries := 0
+	for v := range g.AdjacencyList {
+		entries += len(g.AdjacencyList[v])
+	}
+	return entries
+}
+
+// ============================================================================
+// GRAPHVIZ DOT AND JSON EXPORT
+// ============================================================================
+
+// DOTOptions limits what WriteDOT emits for large graphs
+type DOTOptions struct {
+	Name     string // Graph name, defaults to "maxflow"
+	Vertices []int  // Only edges between these vertices (nil for all)
+	TopEdges int    // Only the k edges carrying the most flow (0 for all)
+}
+
+// exportEdge is one original edge with its current flow
+type exportEdge struct {
+	From      int  `json:"from"`
+	To        int  `json:"to"`
+	Capacity  int  `json:"capacity"`
+	Flow      int  `json:"flow"`
+	Saturated bool `json:"saturated"`
+	Cut       bool `json:"cut"`
+}
+
+// exportGraph is the WriteJSON document
+type exportGraph struct {
+	Vertices   int          `json:"vertices"`
+	Edges      []exportEdge `json:"edges"`
+	Source     int          `json:"source"`
+	Sink       int          `json:"sink"`
+	FlowValue  int          `json:"flowValue"`
+	Algorithm  string       `json:"algorithm,omitempty"`
+	GraphType  string       `json:"graphType,omitempty"`
+	SourceSide []int        `json:"sourceSide,omitempty"`
+}
+
+// exportEdges collects original edges, marking saturation and min-cut edges
+// when a flow has been computed
+func (g *AdaptiveGraph) exportEdges(sourceSide []bool) []exportEdge {
+	edges := make([]exportEdge, 0, g.edges)
+	for u := 0; u < g.vertices; u++ {
+		for _, edge := range g.AdjacencyList[u] {
+			if !edge.Original {
+				continue
+			}
+			e := exportEdge{
+				From:      u,
+				To:        edge.To,
+				Capacity:  edge.Capacity,
+				Flow:      edge.Flow,
+				Saturated: edge.Capacity > 0 && edge.Flow >= edge.Capacity,
+			}
+			if sourceSide != nil {
+				e.Cut = sourceSide[u] && !sourceSide[edge.To]
+			}
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// exportCut returns the min-cut source side of the last run, or nil
+func (g *AdaptiveGraph) exportCut() []bool {
+	if g.lastSource < 0 || g.lastSource >= g.vertices {
+		return nil
+	}
+	return g.residualReachable(g.lastSource)
+}
+
+// netFlowInto returns the flow value arriving at vertex v
+func (g *AdaptiveGraph) netFlowInto(v int) int {
+	total := 0
+	for _, edge := range g.AdjacencyList[v] {
+		// Reverse entries at v mirror flow on edges entering v
+		total -= edge.Flow
+	}
+	return total
+}
+
+// WriteDOT renders the graph in Graphviz DOT: edges are labelled
+// flow/capacity, saturated edges are drawn red and, after MaxFlow, vertices
+// are coloured by min-cut side with cut edges dashed
+func (g *AdaptiveGraph) WriteDOT(w io.Writer, opts DOTOptions) error {
+	sourceSide := g.exportCut()
+	edges := g.exportEdges(sourceSide)
+	
+	if opts.Vertices != nil {
+		keep := make(map[int]bool, len(opts.Vertices))
+		for _, v := range opts.Vertices {
+			keep[v] = true
+		}
+		filtered := edges[:0]
+		for _, e := range edges {
+			if keep[e.From] && keep[e.To] {
+				filtered = append(filtered, e)
+			}
+		}
+		edges = filtered
+	}
+	if opts.TopEdges > 0 && len(edges) > opts.TopEdges {
+		sort.SliceStable(edges, func(i, j int) bool {
+			if edges[i].Flow != edges[j].Flow {
+				return edges[i].Flow > edges[j].Flow
+			}
+			return edges[i].Capacity > edges[j].Capacity
+		})
+		edges = edges[:opts.TopEdges]
+	}
+	
+	// Vertices: requested set, otherwise every endpoint plus the terminals
+	var vertices []int
+	if opts.Vertices != nil {
+		vertices = append(vertices, opts.Vertices...)
+	} else if opts.TopEdges > 0 {
+		seen := make(map[int]bool)
+		for _, e := range edges {
+			for _, v := range [2]int{e.From, e.To} {
+				if !seen[v] {
+					seen[v] = true
+					vertices = append(vertices, v)
+				}
+			}
+		}
+		sort.Ints(vertices)
+	} else {
+		vertices = make([]int, g.vertices)
+		for v := range vertices {
+			vertices[v] = v
+		}
+	}
+	
+	name := opts.Name
+	if name == "" {
+		name = "maxflow"
+	}
+	
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "digraph %q {\n", name)
+	fmt.Fprintf(out, "  rankdir=LR;\n")
+	fmt.Fprintf(out, "  node [shape=circle, style=filled, fillcolor=white];\n")
+	if g.lastSource >= 0 {
+		fmt.Fprintf(out, "  label=%q;\n", fmt.Sprintf("%s, flow %d", g.algorithm, g.netFlowInto(g.lastSink)))
+	}
+	
+	for _, v := range vertices {
+		if v < 0 || v >= g.vertices {
+			continue
+		}
+		var attrs []string
+		switch v {
+		case g.lastSource:
+			attrs = append(attrs, fmt.Sprintf("label=%q", fmt.Sprintf("%d (s)", v)), "shape=doublecircle")
+		case g.lastSink:
+			attrs = append(attrs, fmt.Sprintf("label=%q", fmt.Sprintf("%d (t)", v)), "shape=doublecircle")
+		}
+		if sourceSide != nil {
+			if sourceSide[v] {
+				attrs = append(attrs, `fillcolor="#9ecae1"`)
+			} else {
+				attrs = append(attrs, `fillcolor="#fdd0a2"`)
+			}
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(out, "  %d;\n", v)
+		} else {
+			fmt.Fprintf(out, "  %d [%s];\n", v, strings.Join(attrs, ", "))
+		}
+	}
+	
+	for _, e := range edges {
+		attrs := []string{fmt.Sprintf(`label="%d/%d"`, e.Flow, e.Capacity)}
+		if e.Saturated {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		if e.Cut {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(out, "  %d -> %d [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+	}
+	
+	fmt.Fprintf(out, "}\n")
+	return out.Flush()
+}
+
+// WriteJSON emits every original edge with capacity, flow, saturation and
+// min-cut membership, plus the terminals and flow value of the last run
+func (g *AdaptiveGraph) WriteJSON(w io.Writer) error {
+	sourceSide := g.exportCut()
+	doc := exportGraph{
+		Vertices: g.vertices,
+		Edges:    g.exportEdges(sourceSide),
+		Source:   g.lastSource,
+		Sink:     g.lastSink,
+	}
+	if sourceSide != nil {
+		doc.FlowValue = g.netFlowInto(g.lastSink)
+		doc.Algorithm = g.algorithm.String()
+		doc.GraphType = g.graphType.String()
+		for v, side := range sourceSide {
+			if side {
+				doc.SourceSide = append(doc.SourceSide, v)
+			}
+		}
+	}
+	
+	out := bufio.NewWriter(w)
+	if err := json.NewEncoder(out).Encode(&doc); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// ============================================================================
+// JSON-OVER-HTTP MAX-FLOW SERVICE
+// ============================================================================
+
+// Service defaults; every limit can be overridden on the serve command line
+const (
+	SERVER_MAX_BODY_BYTES = 64 << 20 // Request body limit
+	SERVER_MAX_VERTICES   = 10000000 // Largest accepted graph
+	SERVER_MAX_EDGES      = 50000000
+	SERVER_TIMEOUT        = 30 * time.Second
+)
+
+// FlowServerConfig bounds the work a single FlowServer accepts
+type FlowServerConfig struct {
+	MaxBodyBytes  int64
+	MaxVertices   int
+	MaxEdges      int
+	MaxConcurrent int           // Solver slots, defaults to runtime.NumCPU()
+	Timeout       time.Duration // Upper bound for a single request
+}
+
+// FlowServer answers POST /maxflow with the flow value, min cut, statistics
+// and the algorithm MaxFlow selected
+type FlowServer struct {
+	config FlowServerConfig
+	slots  chan struct{} // Same token scheme as AdaptiveGraph.WorkerPool
+}
+
+// flowRequest is the JSON request body; Edges holds [from, to, capacity]
+// triples, or DIMACS carries a "p max" problem instead
+type flowRequest struct {
+	Vertices  int      `json:"vertices"`
+	Source    int      `json:"source"`
+	Sink      int      `json:"sink"`
+	Edges     [][3]int `json:"edges"`
+	DIMACS    string   `json:"dimacs,omitempty"`
+	TimeoutMs int      `json:"timeoutMs,omitempty"`
+}
+
+type flowResponse struct {
+	Flow       int            `json:"flow"`
+	Algorithm  string         `json:"algorithm"`
+	GraphType  string         `json:"graphType"`
+	MinCut     flowCut        `json:"minCut"`
+	Statistics flowStatistics `json:"statistics"`
+}
+
+type flowCut struct {
+	SourceSide []int    `json:"sourceSide"`
+	Edges      [][2]int `json:"edges"`
+}
+
+type flowStatistics struct {
+	Vertices      int     `json:"vertices"`
+	Edges         int     `json:"edges"`
+	BFSIterations int     `json:"bfsIterations"`
+	DFSIterations int     `json:"dfsIterations"`
+	ComputeMs     float64 `json:"computeMs"`
+}
+
+// flowProblem is a parsed request; base is the first vertex ID of the input
+// numbering (1 for DIMACS) so answers use the caller's IDs
+type flowProblem struct {
+	vertices, source, sink int
+	edges                  [][3]int
+	base                   int
+}
+
+// NewFlowServer fills unset limits with the service defaults
+func NewFlowServer(config FlowServerConfig) *FlowServer {
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = SERVER_MAX_BODY_BYTES
+	}
+	if config.MaxVertices <= 0 {
+		config.MaxVertices = SERVER_MAX_VERTICES
+	}
+	if config.MaxEdges <= 0 {
+		config.MaxEdges = SERVER_MAX_EDGES
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = runtime.NumCPU()
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = SERVER_TIMEOUT
+	}
+	
+	s := &FlowServer{
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrent),
+	}
+	for i := 0; i < config.MaxConcurrent; i++ {
+		s.slots <- struct{}{}
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *FlowServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/maxflow" {
+		writeFlowError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeFlowError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	
+	problem, timeout, status, err := s.parseRequest(w, r)
+	if err != nil {
+		writeFlowError(w, status, err.Error())
+		return
+	}
+	
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	
+	// Wait for a solver slot, giving up when the deadline passes
+	select {
+	case <-s.slots:
+	case <-ctx.Done():
+		writeFlowError(w, http.StatusServiceUnavailable, "all solver slots busy")
+		return
+	}
+	
+	// MaxFlow cannot be interrupted: it keeps its slot until it finishes
+	done := make(chan flowResponse, 1)
+	go func() {
+		defer func() { s.slots <- struct{}{} }()
+		done <- solveFlowProblem(problem)
+	}()
+	
+	select {
+	case response := <-done:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&response)
+	case <-ctx.Done():
+		writeFlowError(w, http.StatusGatewayTimeout, "max flow did not finish within "+timeout.String())
+	}
+}
+
+// parseRequest reads a JSON or DIMACS body within the configured limits
+func (s *FlowServer) parseRequest(w http.ResponseWriter, r *http.Request) (*flowProblem, time.Duration, int, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, 0, http.StatusRequestEntityTooLarge, fmt.Errorf("body exceeds %d bytes", s.config.MaxBodyBytes)
+		}
+		return nil, 0, http.StatusBadRequest, err
+	}
+	
+	timeout := s.config.Timeout
+	var problem *flowProblem
+	
+	if strings.Contains(r.Header.Get("Content-Type"), "json") {
+		var req flowRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, 0, http.StatusBadRequest, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if requested := time.Duration(req.TimeoutMs) * time.Millisecond; requested > 0 && requested < timeout {
+			timeout = requested
+		}
+		if req.DIMACS != "" {
+			problem, err = parseDIMACS(req.DIMACS, s.config.MaxVertices, s.config.MaxEdges)
+		} else {
+			problem = &flowProblem{vertices: req.Vertices, source: req.Source, sink: req.Sink, edges: req.Edges}
+		}
+	} else {
+		problem, err = parseDIMACS(string(body), s.config.MaxVertices, s.config.MaxEdges)
+	}
+	if errors.Is(err, errGraphTooLarge) {
+		return nil, 0, http.StatusRequestEntityTooLarge, err
+	}
+	if err != nil {
+		return nil, 0, http.StatusBadRequest, err
+	}
+	
+	if problem.vertices > s.config.MaxVertices || len(problem.edges) > s.config.MaxEdges {
+		return nil, 0, http.StatusRequestEntityTooLarge, fmt.Errorf("graph exceeds %d vertices or %d edges", s.config.MaxVertices, s.config.MaxEdges)
+	}
+	if err := problem.validate(); err != nil {
+		return nil, 0, http.StatusBadRequest, err
+	}
+	return problem, timeout, http.StatusOK, nil
+}
+
+func (p *flowProblem) validate() error {
+	if p.vertices < 2 {
+		return errors.New("graph needs at least 2 vertices")
+	}
+	inRange := func(v int) bool { return v >= 0 && v < p.vertices }
+	if !inRange(p.source) || !inRange(p.sink) || p.source == p.sink {
+		return fmt.Errorf("invalid terminals %d and %d", p.source+p.base, p.sink+p.base)
+	}
+	for _, e := range p.edges {
+		if !inRange(e[0]) || !inRange(e[1]) || e[2] < 0 {
+			return fmt.Errorf("invalid edge %d -> %d (capacity %d)", e[0]+p.base, e[1]+p.base, e[2])
+		}
+	}
+	return nil
+}
+
+// errGraphTooLarge rejects a DIMACS header over the server limits before
+// anything is allocated for it
+var errGraphTooLarge = errors.New("graph exceeds the vertex or edge limit")
+
+// parseDIMACS reads the DIMACS max-flow format ("p max", "n id s|t", "a u v c").
+// The header sizes are untrusted: they are checked against the limits and
+// nothing is preallocated from them.
+func parseDIMACS(text string, maxVertices, maxEdges int) (*flowProblem, error) {
+	p := &flowProblem{vertices: -1, source: -1, sink: -1, base: 1}
+	
+	for lineNo, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		
+		switch {
+		case fields[0] == "p" && len(fields) == 4 && fields[1] == "max":
+			numbers, err := dimacsInts(lineNo, fields[2:])
+			if err != nil {
+				return nil, err
+			}
+			if numbers[0] < 0 || numbers[1] < 0 {
+				return nil, fmt.Errorf("dimacs line %d: negative size in %q", lineNo+1, line)
+			}
+			if numbers[0] > maxVertices || numbers[1] > maxEdges {
+				return nil, fmt.Errorf("dimacs line %d: %w (%d vertices, %d edges)", lineNo+1, errGraphTooLarge, maxVertices, maxEdges)
+			}
+			p.vertices = numbers[0]
+		case fields[0] == "n" && len(fields) == 3:
+			numbers, err := dimacsInts(lineNo, fields[1:2])
+			if err != nil {
+				return nil, err
+			}
+			switch fields[2] {
+			case "s":
+				p.source = numbers[0] - 1
+			case "t":
+				p.sink = numbers[0] - 1
+			default:
+				return nil, fmt.Errorf("dimacs line %d: unknown node type %q", lineNo+1, fields[2])
+			}
+		case fields[0] == "a" && len(fields) == 4:
+			numbers, err := dimacsInts(lineNo, fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			p.edges = append(p.edges, [3]int{numbers[0] - 1, numbers[1] - 1, numbers[2]})
+		default:
+			return nil, fmt.Errorf("dimacs line %d: cannot parse %q", lineNo+1, line)
+		}
+	}
+	
+	if p.vertices < 0 {
+		return nil, errors.New("dimacs: missing \"p max\" line")
+	}
+	return p, nil
+}
+
+// dimacsInts parses every field as an integer, naming the line on failure
+func dimacsInts(lineNo int, fields []string) ([]int, error) {
+	numbers := make([]int, len(fields))
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("dimacs line %d: %w", lineNo+1, err)
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+// solveFlowProblem runs MinCut and reports the answer in request vertex IDs
+func solveFlowProblem(p *flowProblem) flowResponse {
+	g := NewAdaptiveGraph(p.vertices)
+	for _, e := range p.edges {
+		g.AddEdge(e[0], e[1], e[2])
+	}
+	
+	start := time.Now()
+	flow, sourceSide := g.MinCut(p.source, p.sink)
+	elapsed := time.Since(start)
+	
+	response := flowResponse{
+		Flow:      flow,
+		Algorithm: g.algorithm.String(),
+		GraphType: g.graphType.String(),
+		MinCut:    flowCut{SourceSide: []int{}, Edges: [][2]int{}},
+		Statistics: flowStatistics{
+			Vertices:      g.vertices,
+			Edges:         g.edges,
+			BFSIterations: g.bfsIterations,
+			DFSIterations: g.dfsIterations,
+			ComputeMs:     float64(elapsed.Microseconds()) / 1000,
+		},
+	}
+	for v := 0; v < p.vertices; v++ {
+		if sourceSide[v] {
+			response.MinCut.SourceSide = append(response.MinCut.SourceSide, v+p.base)
+		}
+	}
+	for _, e := range p.edges {
+		if sourceSide[e[0]] && !sourceSide[e[1]] && e[2] > 0 {
+			response.MinCut.Edges = append(response.MinCut.Edges, [2]int{e[0] + p.base, e[1] + p.base})
+		}
+	}
+	return response
+}
+
+func writeFlowError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// runServer implements the "serve" command
+func runServer(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "listen address")
+	maxBody := flags.Int64("max-body", SERVER_MAX_BODY_BYTES, "request body limit in bytes")
+	maxVertices := flags.Int("max-vertices", SERVER_MAX_VERTICES, "largest accepted vertex count")
+	maxEdges := flags.Int("max-edges", SERVER_MAX_EDGES, "largest accepted edge count")
+	concurrency := flags.Int("concurrency", runtime.NumCPU(), "concurrent max-flow computations")
+	timeout := flags.Duration("timeout", SERVER_TIMEOUT, "per-request time limit")
+	flags.Parse(args)
+	
+	server := NewFlowServer(FlowServerConfig{
+		MaxBodyBytes:  *maxBody,
+		MaxVertices:   *maxVertices,
+		MaxEdges:      *maxEdges,
+		MaxConcurrent: *concurrency,
+		Timeout:       *timeout,
+	})
+	
+	fmt.Printf("Max-flow service listening on %s (POST /maxflow)\n", *addr)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// ============================================================================
+// LIBRARY INTERFACE - FOR TESTING USE 23B-adaptive-kyng-dinics-TEST.go
+// ============================================================================
+
+// This file provides the core algorithm implementation.
+
+func main() {
+	// "serve" runs the JSON-over-HTTP service instead of the demo
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServer(os.Args[2:])
+		return
+	}
+	
 	// Simple test
 	g := NewAdaptiveGraph(6)
 	g.AddEdge(0, 1, 10)
@@ -1,8 +1,22 @@
 package main
 
 import (
+	"encoding"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -126,8 +140,9 @@ func TestPatternDetection(t *testing.T) {
 	}
 	
 	// Check that patterns were detected
+	stats := db.Stats()
 	totalPatterns := uint64(0)
-	for _, count := range db.patternStats {
+	for _, count := range stats.Patterns {
 		totalPatterns += count
 	}
 	
@@ -136,13 +151,13 @@ func TestPatternDetection(t *testing.T) {
 	}
 	
 	// Should have detected at least some special values, integers, and simple decimals
-	if db.patternStats[PatternSpecialValue] == 0 {
+	if stats.Patterns[PatternSpecialValue] == 0 {
 		t.Error("Should have detected special values")
 	}
-	if db.patternStats[PatternInteger] == 0 {
+	if stats.Patterns[PatternInteger] == 0 {
 		t.Error("Should have detected integers")
 	}
-	if db.patternStats[PatternSimpleDecimal] == 0 {
+	if stats.Patterns[PatternSimpleDecimal] == 0 {
 		t.Error("Should have detected simple decimals")
 	}
 }
@@ -163,7 +178,7 @@ func TestRangeSelection(t *testing.T) {
 	
 	// Check that different ranges were used
 	totalRanges := uint64(0)
-	for _, count := range db.rangeStats {
+	for _, count := range db.Stats().Ranges {
 		totalRanges += count
 	}
 	
@@ -188,11 +203,2210 @@ func TestCaching(t *testing.T) {
 		t.Errorf("Cached results should match: %s != %s", result1, result2)
 	}
 	
-	if db.cacheHits == 0 {
+	if db.Stats().CacheHits == 0 {
 		t.Error("Should have at least one cache hit")
 	}
 }
 
+func TestStatsConcurrent(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	values := generateMixed(2000)
+	narrow := make([]float32, 500)
+	for i := range narrow {
+		narrow[i] = float32(values[i])
+	}
+	const goroutines, rounds = 8, 3
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				if g%2 == 0 {
+					for _, f := range values {
+						_ = db.Convert(f)
+					}
+				} else {
+					_ = db.BatchConvert(values)
+				}
+				_ = db.ConvertFloat32(float32(values[g]))
+				_ = db.BatchConvertFloat32(narrow)
+				_ = db.Stats()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	stats := db.Stats()
+	lookups := uint64(goroutines * rounds * len(values))
+	if stats.CacheHits+stats.CacheMisses != lookups {
+		t.Errorf("hits %d + misses %d = %d, want %d lookups",
+			stats.CacheHits, stats.CacheMisses, stats.CacheHits+stats.CacheMisses, lookups)
+	}
+	if want := stats.CacheMisses + goroutines*rounds*uint64(1+len(narrow)); stats.Conversions != want {
+		t.Errorf("Conversions = %d, want %d (misses plus float32 values)", stats.Conversions, want)
+	}
+	var patterns uint64
+	for _, count := range stats.Patterns {
+		patterns += count
+	}
+	if patterns != stats.Conversions {
+		t.Errorf("pattern counts sum to %d, want %d", patterns, stats.Conversions)
+	}
+	if rate := stats.CacheHitRate(); rate <= 0 || rate > 1 {
+		t.Errorf("CacheHitRate = %v", rate)
+	}
+	if !strings.Contains(stats.String(), "Total Conversions") {
+		t.Errorf("Stats report missing totals:\n%s", stats)
+	}
+}
+
+func TestClockCache(t *testing.T) {
+	c := NewClockCache(256)
+	hot := math.Float64bits(math.Pi)
+	c.Put(hot, "3.141592653589793")
+
+	// A stream of 100k distinct values must not push out a value that
+	// keeps being read
+	for i := 0; i < 100000; i++ {
+		c.Put(math.Float64bits(float64(i)+0.5), "x")
+		if i%10 == 0 {
+			if _, ok := c.Get(hot); !ok {
+				t.Fatalf("hot entry evicted after %d inserts", i)
+			}
+		}
+	}
+
+	m := c.Metrics()
+	if m.Capacity < 256 || m.Len != m.Capacity {
+		t.Errorf("Len %d, Capacity %d; want a full cache of at least 256", m.Len, m.Capacity)
+	}
+	if m.Evictions != uint64(100001-m.Len) {
+		t.Errorf("Evictions = %d, want %d", m.Evictions, 100001-m.Len)
+	}
+	if m.Hits != 10000 || m.Misses != 0 {
+		t.Errorf("Hits %d, Misses %d; want 10000 and 0", m.Hits, m.Misses)
+	}
+	if _, ok := c.Get(math.Float64bits(0.5)); ok {
+		t.Error("oldest unreferenced entry survived")
+	}
+
+	// Overwrites keep the slot
+	c.Put(hot, "pi")
+	if s, _ := c.Get(hot); s != "pi" {
+		t.Errorf("Get after overwrite = %q", s)
+	}
+}
+
+func TestConverterCacheModes(t *testing.T) {
+	values := generateMixed(3000)
+	want := NewUnifiedDragonbox().BatchConvert(values)
+
+	uncached := NewUnifiedDragonboxWithCache(FormatOptions{}, NoCache())
+	small := NewUnifiedDragonboxWithCache(FormatOptions{}, NewClockCache(64))
+	for round := 0; round < 2; round++ {
+		for i, f := range values {
+			if got := uncached.Convert(f); got != want[i] {
+				t.Fatalf("NoCache Convert(%v) = %s, want %s", f, got, want[i])
+			}
+			if got := small.Convert(f); got != want[i] {
+				t.Fatalf("small cache Convert(%v) = %s, want %s", f, got, want[i])
+			}
+		}
+	}
+
+	if stats := uncached.Stats(); stats.CacheHits != 0 || stats.Conversions != uint64(2*len(values)) {
+		t.Errorf("NoCache hits %d, conversions %d", stats.CacheHits, stats.Conversions)
+	}
+	if m := small.Stats().Cache; m.Evictions == 0 || m.Len > m.Capacity {
+		t.Errorf("small cache metrics %+v, want evictions within capacity", m)
+	}
+	if m := NewUnifiedDragonboxWithCache(FormatOptions{}, nil).Stats().Cache; m.Capacity < DefaultCacheSize {
+		t.Errorf("nil cache capacity %d, want the default %d", m.Capacity, DefaultCacheSize)
+	}
+
+	// The default grows with GOMAXPROCS so shards keep their size
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(64))
+	if m := DefaultCache().Metrics(); m.Capacity < 64*DefaultCacheShardSize {
+		t.Errorf("DefaultCache capacity %d at GOMAXPROCS 64, want at least %d", m.Capacity, 64*DefaultCacheShardSize)
+	}
+}
+
+func TestClockCacheConcurrent(t *testing.T) {
+	c := NewClockCache(512)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				bits := uint64(i % (1000 + g*100))
+				if _, ok := c.Get(bits); !ok {
+					c.Put(bits, fmt.Sprint(bits))
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	m := c.Metrics()
+	if m.Hits+m.Misses != 8*5000 || m.Len > m.Capacity {
+		t.Errorf("metrics %+v after 40000 lookups", m)
+	}
+}
+
+// Differential sample count; sweeps of billions run as e.g.
+// go test -run TestShortestDifferential -dragonbox.samples=4000000000 -timeout 0
+var differentialSamples = flag.Uint64("dragonbox.samples", 1<<20, "random bit patterns checked against strconv")
+
+// splitDigits reduces a decimal or scientific string to its significant
+// digits and the position of the decimal point relative to them
+func splitDigits(s string) (string, int) {
+	s = strings.TrimLeft(s, "+-")
+	exponent := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exponent, _ = strconv.Atoi(s[i+1:])
+		s = s[:i]
+	}
+	point := len(s)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		point = i
+		s = s[:i] + s[i+1:]
+	}
+	trimmed := strings.TrimLeft(s, "0")
+	point -= len(s) - len(trimmed)
+	return strings.TrimRight(trimmed, "0"), point + exponent
+}
+
+// checkShortest compares the Dragonbox digits of f with strconv's shortest
+// output and verifies the formatted result parses back to the same bits
+func checkShortest(f float64) error {
+	got := formatDecimal(dragonbox(f))
+	want := strconv.FormatFloat(f, 'g', -1, 64)
+
+	gotDigits, gotPoint := splitDigits(got)
+	wantDigits, wantPoint := splitDigits(want)
+	if gotDigits != wantDigits || (gotDigits != "" && gotPoint != wantPoint) {
+		return fmt.Errorf("%#016x: got %s, want %s", math.Float64bits(f), got, want)
+	}
+
+	parsed, err := strconv.ParseFloat(got, 64)
+	if err != nil || math.Float64bits(parsed) != math.Float64bits(f) {
+		return fmt.Errorf("%#016x: %s reads back as %v (%v)", math.Float64bits(f), got, parsed, err)
+	}
+	return nil
+}
+
+func TestShortestBoundaries(t *testing.T) {
+	var values []float64
+
+	// Every power of two (asymmetric interval) and its neighbours
+	for e := uint64(1); e < 2047; e++ {
+		bits := e << SignificandBits
+		values = append(values,
+			math.Float64frombits(bits),
+			math.Float64frombits(bits-1),
+			math.Float64frombits(bits+1))
+	}
+
+	// Subnormals, extremes and known hard cases
+	for i := uint64(1); i < 1000; i++ {
+		values = append(values, math.Float64frombits(i), math.Float64frombits(SignificandMask-i))
+	}
+	values = append(values,
+		math.SmallestNonzeroFloat64, math.MaxFloat64, 0x1p-1022,
+		5e-324, 1e23, 9007199254740993, 2.9802322387695312e-08,
+		0x1p-77, 0x1p-76, 0x1p-78, 0.3, 2.0/3, 123456789012345680)
+	for k := -323; k <= 308; k++ {
+		values = append(values, math.Pow10(k))
+	}
+
+	for _, f := range values {
+		for _, v := range []float64{f, -f} {
+			if err := checkShortest(v); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	// Reference cache entry for 10^-292 from the Dragonbox paper's table
+	if c := dragonboxCache(-292); c.Hi != 0xff77b1fcbebcdc4f || c.Lo != 0x25e8e89c13bb0f7b {
+		t.Errorf("dragonboxCache(-292) = %016x%016x", c.Hi, c.Lo)
+	}
+}
+
+var generatePowerTable = flag.Bool("dragonbox.gentable", false, "print powerTable as Go source, recomputed with math/big")
+
+// TestPowerTable recomputes every generated entry with math/big; with
+// -dragonbox.gentable it prints the table source to paste over powerTable
+func TestPowerTable(t *testing.T) {
+	var source strings.Builder
+	for i, entry := range powerTable {
+		k := int32(i) + MinPower10
+		want := computePower10(k)
+		if entry != want {
+			t.Errorf("powerTable 1e%d = %016x%016x, want %016x%016x", k, entry.Hi, entry.Lo, want.Hi, want.Lo)
+		}
+		fmt.Fprintf(&source, "\t{0x%016x, 0x%016x}, // 1e%d\n", want.Hi, want.Lo, k)
+	}
+	if *generatePowerTable {
+		fmt.Print(source.String())
+	}
+
+	// The tiers and the converter's tables alias powerTable
+	db := NewUnifiedDragonbox()
+	for _, view := range []struct {
+		name  string
+		table []Power10Entry
+		min   int32
+		size  int
+	}{
+		{"compactPowers", compactPowers, -20, CompactCacheSize},
+		{"mediumPowers", mediumPowers, -100, MediumCacheSize},
+		{"compactTable", db.compactTable, -20, CompactCacheSize},
+		{"mediumTable", db.mediumTable, -100, MediumCacheSize},
+		{"fullTable", db.fullTable, MinPower10, PowerTableSize},
+	} {
+		if len(view.table) != view.size || &view.table[0] != &powerTable[view.min-MinPower10] {
+			t.Errorf("%s is not a %d-entry view from 1e%d", view.name, view.size, view.min)
+		}
+	}
+	for k := int32(MinPower10); k <= MaxPower10; k++ {
+		if lookupPower10(k) != powerTable[k-MinPower10] {
+			t.Errorf("lookupPower10(%d) disagrees with powerTable", k)
+		}
+	}
+
+	// The binary32 table is rounded up from the same entries
+	for i, entry := range float32PowerTable {
+		k := int32(i) + MinFloat32Power10
+		if want := computeFloat32Power10(k); entry != want {
+			t.Errorf("float32PowerTable 1e%d = %016x, want %016x", k, entry, want)
+		}
+	}
+}
+
+// computePower10 returns 10^k as a 128-bit significand with the top bit
+// set, rounded down, so 10^k ~ entry * 2^(floorLog2Pow10(k) - 127)
+func computePower10(k int32) Power10Entry {
+	ten := big.NewInt(10)
+	value := new(big.Int)
+
+	if k >= 0 {
+		value.Exp(ten, big.NewInt(int64(k)), nil)
+		if shift := 128 - value.BitLen(); shift >= 0 {
+			value.Lsh(value, uint(shift))
+		} else {
+			value.Rsh(value, uint(-shift))
+		}
+	} else {
+		// floor(2^(127 + bitlen(10^-k)) / 10^-k) lies in [2^127, 2^128)
+		divisor := new(big.Int).Exp(ten, big.NewInt(int64(-k)), nil)
+		value.Lsh(big.NewInt(1), uint(127+divisor.BitLen()))
+		value.Quo(value, divisor)
+	}
+
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	lo := new(big.Int).And(value, mask).Uint64()
+	hi := value.Rsh(value, 64).Uint64()
+	return Power10Entry{Hi: hi, Lo: lo}
+}
+
+// computeFloat32Power10 returns 10^k normalized to 64 bits, rounded up
+func computeFloat32Power10(k int32) uint64 {
+	ten := big.NewInt(10)
+	value := new(big.Int)
+	inexact := false
+
+	if k >= 0 {
+		value.Exp(ten, big.NewInt(int64(k)), nil)
+		if shift := 64 - value.BitLen(); shift >= 0 {
+			value.Lsh(value, uint(shift))
+		} else {
+			inexact = value.TrailingZeroBits() < uint(-shift)
+			value.Rsh(value, uint(-shift))
+		}
+	} else {
+		divisor := new(big.Int).Exp(ten, big.NewInt(int64(-k)), nil)
+		value.Lsh(big.NewInt(1), uint(63+divisor.BitLen()))
+		value.Quo(value, divisor)
+		inexact = true
+	}
+
+	if inexact {
+		return value.Uint64() + 1
+	}
+	return value.Uint64()
+}
+
+func TestShortestDifferential(t *testing.T) {
+	samples := *differentialSamples
+	if testing.Short() {
+		samples = 1 << 16
+	}
+
+	workers := uint64(runtime.GOMAXPROCS(0))
+	seed := time.Now().UnixNano()
+	t.Logf("checking %d random bit patterns (seed %d)", samples, seed)
+
+	var failures atomic.Int64
+	var wg sync.WaitGroup
+	for w := uint64(0); w < workers; w++ {
+		wg.Add(1)
+		go func(w uint64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed + int64(w)))
+			for i := w; i < samples && failures.Load() < 10; i += workers {
+				f := math.Float64frombits(rng.Uint64())
+				if math.IsNaN(f) || math.IsInf(f, 0) {
+					continue
+				}
+				if err := checkShortest(f); err != nil {
+					failures.Add(1)
+					t.Error(err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// checkShortestFloat32 is checkShortest for binary32
+func checkShortestFloat32(f float32) error {
+	got := formatDecimal(dragonboxFloat32(f))
+	want := strconv.FormatFloat(float64(f), 'g', -1, 32)
+
+	gotDigits, gotPoint := splitDigits(got)
+	wantDigits, wantPoint := splitDigits(want)
+	if gotDigits != wantDigits || (gotDigits != "" && gotPoint != wantPoint) {
+		return fmt.Errorf("%#08x: got %s, want %s", math.Float32bits(f), got, want)
+	}
+
+	parsed, err := strconv.ParseFloat(got, 32)
+	if err != nil || math.Float32bits(float32(parsed)) != math.Float32bits(f) {
+		return fmt.Errorf("%#08x: %s reads back as %v (%v)", math.Float32bits(f), got, parsed, err)
+	}
+	return nil
+}
+
+func TestShortestFloat32(t *testing.T) {
+	// Every power of two, its neighbours, and the subnormal range edges
+	for e := uint32(1); e < 255; e++ {
+		bits := e << Float32SignificandBits
+		for _, b := range []uint32{bits, bits - 1, bits + 1} {
+			if err := checkShortestFloat32(math.Float32frombits(b)); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	for i := uint32(1); i < 1000; i++ {
+		for _, b := range []uint32{i, Float32SignificandMask - i} {
+			if err := checkShortestFloat32(math.Float32frombits(b)); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	// Random patterns, or all 2^32 of them once the sample count allows
+	samples := *differentialSamples
+	if testing.Short() {
+		samples = 1 << 16
+	}
+	exhaustive := samples >= 1<<32
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	failures := 0
+	for i := uint64(0); i < samples && i < 1<<32 && failures < 10; i++ {
+		bits := rng.Uint32()
+		if exhaustive {
+			bits = uint32(i)
+		}
+		f := math.Float32frombits(bits)
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			continue
+		}
+		if err := checkShortestFloat32(f); err != nil {
+			failures++
+			t.Error(err)
+		}
+	}
+}
+
+func TestConvertFloat32(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	tests := []struct {
+		input    float32
+		expected string
+	}{
+		{0.1, "0.1"},
+		{0.01, "0.01"},
+		{0.001, "0.001"},
+		{1.0 / 3, "0.33333334"},
+		{16777216, "16777216"},
+		{-42, "-42"},
+		{3.4028235e38, "3.4028235e38"},
+		{1e-45, "1e-45"},
+		{float32(math.Inf(-1)), "-Inf"},
+	}
+
+	for _, tt := range tests {
+		if result := db.ConvertFloat32(tt.input); result != tt.expected {
+			t.Errorf("ConvertFloat32(%v) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+
+	// Batches go through the same fast paths
+	values := make([]float32, 1000)
+	for i := range values {
+		values[i] = float32(i)*0.37 - 100
+	}
+	for i, result := range db.BatchConvertFloat32(values) {
+		parsed, err := strconv.ParseFloat(result, 32)
+		if err != nil || float32(parsed) != values[i] {
+			t.Errorf("BatchConvertFloat32[%d] = %s, want %v", i, result, values[i])
+		}
+		if result != db.ConvertFloat32(values[i]) {
+			t.Errorf("BatchConvertFloat32[%d] = %s differs from ConvertFloat32", i, result)
+		}
+	}
+}
+
+// checkShortestHalf compares Convert for one positive 16-bit value with a
+// big.Rat search over the exact rounding interval: same digit count, and
+// no other candidate of that count is closer
+func checkShortestHalf(hf *halfFormat, h uint16, got string) error {
+	x := hf.toFloat64(h)
+	exact := new(big.Rat).SetFloat64(x)
+
+	prev := new(big.Rat).SetFloat64(hf.toFloat64(h - 1))
+	next := new(big.Rat)
+	if h+1 == hf.exponentMask()<<hf.significandBits {
+		// Past the largest finite value the next step is the same width
+		next.Sub(next.Add(exact, exact), prev)
+	} else {
+		next.SetFloat64(hf.toFloat64(h + 1))
+	}
+	half := big.NewRat(1, 2)
+	lo := new(big.Rat).Mul(new(big.Rat).Add(prev, exact), half)
+	hi := new(big.Rat).Mul(new(big.Rat).Add(exact, next), half)
+	inclusive := h&1 == 0
+	inside := func(r *big.Rat) bool {
+		cl, ch := r.Cmp(lo), r.Cmp(hi)
+		return (cl > 0 && ch < 0) || (inclusive && (cl == 0 || ch == 0))
+	}
+
+	e10 := int(math.Floor(math.Log10(x)))
+	pow := func(p int) *big.Rat {
+		r := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(p, -p))), nil))
+		if p < 0 {
+			r.Inv(r)
+		}
+		return r
+	}
+	for pow(e10).Cmp(exact) > 0 {
+		e10--
+	}
+	for pow(e10+1).Cmp(exact) <= 0 {
+		e10++
+	}
+
+	parsed, ok := new(big.Rat).SetString(got)
+	if !ok {
+		return fmt.Errorf("%#04x: %q does not parse", h, got)
+	}
+	distance := func(r *big.Rat) *big.Rat {
+		return new(big.Rat).Abs(new(big.Rat).Sub(r, exact))
+	}
+
+	for n := 1; n <= 17; n++ {
+		scale := pow(e10 - (n - 1))
+		q := new(big.Rat).Quo(exact, scale)
+		floor := new(big.Int).Quo(q.Num(), q.Denom())
+		var found []*big.Rat
+		for _, c := range []*big.Int{floor, new(big.Int).Add(floor, big.NewInt(1))} {
+			candidate := new(big.Rat).Mul(new(big.Rat).SetInt(c), scale)
+			if inside(candidate) {
+				found = append(found, candidate)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		if !inside(parsed) {
+			return fmt.Errorf("%#04x: %q does not round-trip", h, got)
+		}
+		digits := strings.TrimLeft(strings.ReplaceAll(strings.Split(got, "e")[0], ".", ""), "0")
+		if len(strings.TrimRight(digits, "0")) > n {
+			return fmt.Errorf("%#04x: %q is longer than %d digits", h, got, n)
+		}
+		for _, candidate := range found {
+			if distance(candidate).Cmp(distance(parsed)) < 0 {
+				return fmt.Errorf("%#04x: %q is farther than %s", h, got, candidate.FloatString(20))
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%#04x: no candidate found", h)
+}
+
+func TestShortestHalf(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	step := 1
+	if testing.Short() {
+		step = 7
+	}
+
+	for _, tc := range []struct {
+		name    string
+		hf      *halfFormat
+		convert func(uint16) string
+	}{
+		{"float16", float16Format, db.ConvertFloat16},
+		{"bfloat16", bfloat16Format, db.ConvertBFloat16},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			failures := 0
+			infinity := tc.hf.exponentMask() << tc.hf.significandBits
+			for h := uint16(1); h < infinity && failures < 10; h += uint16(step) {
+				got := tc.convert(h)
+				if err := checkShortestHalf(tc.hf, h, got); err != nil {
+					failures++
+					t.Error(err)
+				}
+				if neg := tc.convert(h | 0x8000); neg != "-"+got {
+					failures++
+					t.Errorf("%#04x: negative prints %q, want -%s", h, neg, got)
+				}
+			}
+
+			// Every common fraction entry is the shortest form of its bits
+			for h, want := range tc.hf.commonFractions {
+				if got := formatDecimal(tc.hf.shortest(h)); got != want {
+					t.Errorf("common fraction %#04x = %s, shortest is %s", h, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertHalf(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	tests := []struct {
+		bits     uint16
+		float16  string
+		bfloat16 string
+	}{
+		{0x0000, "0", "0"},
+		{0x8000, "-0", "-0"},
+		{0x0001, "6e-8", "9e-41"},
+		{0x2E66, "0.1", "5.23e-11"},
+		{0x3C00, "1", "0.0078"},
+		{0x3C01, "1.001", "0.0079"},
+		{0x3DCD, "1.45", "0.1"},
+		{0x4780, "7.5", "65500"},
+		{0x7BFF, "65500", "2.65e36"},
+		{0x7C00, "+Inf", "2.66e36"},
+		{0xFC00, "-Inf", "-2.66e36"},
+		{0x7E00, "NaN", "4.25e37"},
+		{0x7F80, "NaN", "+Inf"},
+		{0x7F7F, "NaN", "3.39e38"},
+	}
+
+	for _, tt := range tests {
+		if got := db.ConvertFloat16(tt.bits); got != tt.float16 {
+			t.Errorf("ConvertFloat16(%#04x) = %s, want %s", tt.bits, got, tt.float16)
+		}
+		if got := db.ConvertBFloat16(tt.bits); got != tt.bfloat16 {
+			t.Errorf("ConvertBFloat16(%#04x) = %s, want %s", tt.bits, got, tt.bfloat16)
+		}
+	}
+
+	// Batches go through the same fast paths
+	rng := rand.New(rand.NewSource(1))
+	values := make([]uint16, 1000)
+	for i := range values {
+		values[i] = uint16(rng.Uint32())
+	}
+	for i, result := range db.BatchConvertFloat16(values) {
+		if want := db.ConvertFloat16(values[i]); result != want {
+			t.Errorf("BatchConvertFloat16[%d] = %s, want %s", i, result, want)
+		}
+	}
+	for i, result := range db.BatchConvertBFloat16(values) {
+		if want := db.ConvertBFloat16(values[i]); result != want {
+			t.Errorf("BatchConvertBFloat16[%d] = %s, want %s", i, result, want)
+		}
+	}
+}
+
+func TestHalfConversions(t *testing.T) {
+	// Widening is exact, so narrowing the widened value is the identity
+	for i := 0; i < 1<<16; i++ {
+		h := uint16(i)
+		if f := Float16ToFloat64(h); !math.IsNaN(f) && Float64ToFloat16(f) != h {
+			t.Fatalf("float16 %#04x widens to %v, narrows to %#04x", h, f, Float64ToFloat16(f))
+		}
+		if f := BFloat16ToFloat64(h); !math.IsNaN(f) && Float64ToBFloat16(f) != h {
+			t.Fatalf("bfloat16 %#04x widens to %v, narrows to %#04x", h, f, Float64ToBFloat16(f))
+		}
+	}
+
+	tests := []struct {
+		input    float64
+		float16  uint16
+		bfloat16 uint16
+	}{
+		{1 + 0x1p-11, 0x3C00, 0x3F80},                 // float16 tie to even
+		{1 + 3*0x1p-11, 0x3C02, 0x3F80},               // float16 tie to even, upward
+		{1 + 0x1p-8 + 0x1p-30, 0x3C04, 0x3F81},        // float32 would round to the tie first
+		{65519, 0x7BFF, 0x4780},                       // largest float16 below overflow
+		{65520, 0x7C00, 0x4780},                       // ties to even go to Inf
+		{0x1p-25, 0x0000, 0x3300},                     // half the smallest float16 subnormal
+		{0x1p-25 + 0x1p-40, 0x0001, 0x3300},           // just above it
+		{-0x1p-133, 0x8000, 0x8001},                   // smallest bfloat16 subnormal
+		{math.MaxFloat64, 0x7C00, 0x7F80},             // overflow
+		{math.SmallestNonzeroFloat64, 0x0000, 0x0000}, // underflow
+	}
+	for _, tt := range tests {
+		if got := Float64ToFloat16(tt.input); got != tt.float16 {
+			t.Errorf("Float64ToFloat16(%v) = %#04x, want %#04x", tt.input, got, tt.float16)
+		}
+		if got := Float64ToBFloat16(tt.input); got != tt.bfloat16 {
+			t.Errorf("Float64ToBFloat16(%v) = %#04x, want %#04x", tt.input, got, tt.bfloat16)
+		}
+	}
+
+	// NaNs stay quiet NaNs
+	if h := Float64ToFloat16(math.NaN()); h&0x7C00 != 0x7C00 || h&0x0200 == 0 {
+		t.Errorf("Float64ToFloat16(NaN) = %#04x", h)
+	}
+	if h := Float64ToBFloat16(math.NaN()); h&0x7F80 != 0x7F80 || h&0x0040 == 0 {
+		t.Errorf("Float64ToBFloat16(NaN) = %#04x", h)
+	}
+}
+
+func TestParseHalf(t *testing.T) {
+	tests := []struct {
+		input string
+		parse func(string) (uint16, error)
+		want  uint16
+		err   error
+	}{
+		{"0.1", ParseFloat16, 0x2E66, nil},
+		{"-0", ParseFloat16, 0x8000, nil},
+		{"65504", ParseFloat16, 0x7BFF, nil},
+		{"65519.99", ParseFloat16, 0x7BFF, nil},
+		{"65520", ParseFloat16, 0x7C00, ErrRange},
+		{"-1e10", ParseFloat16, 0xFC00, ErrRange},
+		{"-Inf", ParseFloat16, 0xFC00, nil},
+		{"2.98023223876953125e-8", ParseFloat16, 0x0000, nil},
+		{"2.98023223876953125000001e-8", ParseFloat16, 0x0001, nil},
+
+		// Decimals the float64 parse puts exactly on a float16 midpoint
+		{"1.00048828125", ParseFloat16, 0x3C00, nil},
+		{"1.00048828125000000001", ParseFloat16, 0x3C01, nil},
+		{"-1.00048828125000000001", ParseFloat16, 0xBC01, nil},
+		{"1.000488281249999999999", ParseFloat16, 0x3C00, nil},
+		{"1.00146484375", ParseFloat16, 0x3C02, nil},
+		{"1.00146484374999999999", ParseFloat16, 0x3C01, nil},
+
+		{"0.1", ParseBFloat16, 0x3DCD, nil},
+		{"3.4e38", ParseBFloat16, 0x7F80, ErrRange},
+		{"1.00390625", ParseBFloat16, 0x3F80, nil},
+		{"1.00390625000000000001", ParseBFloat16, 0x3F81, nil},
+		{"1.01171875", ParseBFloat16, 0x3F82, nil},
+		{"1.01171874999999999999", ParseBFloat16, 0x3F81, nil},
+		{"9.2e-41", ParseBFloat16, 0x0001, nil},
+
+		{"", ParseFloat16, 0, ErrSyntax},
+		{"1x", ParseBFloat16, 0, ErrSyntax},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.parse(tt.input)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("parse %q = %#04x, %v; want %#04x, %v", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+
+	if h, err := ParseFloat16("NaN"); err != nil || h&0x7C00 != 0x7C00 || h&0x03FF == 0 {
+		t.Errorf("ParseFloat16(NaN) = %#04x, %v", h, err)
+	}
+}
+
+func TestParseHalfs(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	values := make([]uint16, 0, 1<<15)
+	for i := 0; i < 1<<16; i += 2 {
+		if h := uint16(i); h&0x7C00 != 0x7C00 {
+			values = append(values, h)
+		}
+	}
+
+	parsed, err := ParseFloat16s(db.BatchConvertFloat16(values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, h := range parsed {
+		if h != values[i] {
+			t.Fatalf("ParseFloat16s[%d] = %#04x, want %#04x", i, h, values[i])
+		}
+	}
+
+	strs := db.BatchConvertBFloat16(values)
+	strs[500] = "bad"
+	if _, err := ParseBFloat16s(strs); !errors.Is(err, ErrSyntax) || !strings.Contains(err.Error(), "element 500") {
+		t.Errorf("ParseBFloat16s error = %v, want element 500 syntax error", err)
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	rng := rand.New(rand.NewSource(1))
+
+	values := append(generateMixed(10000), generateScientific(10000)...)
+	for i := 0; i < 10000; i++ {
+		values = append(values, math.Float64frombits(rng.Uint64()&^(ExponentMask<<SignificandBits)))
+	}
+
+	for _, f := range values {
+		s := db.Convert(f)
+		parsed, err := strconv.ParseFloat(s, 64)
+		if err != nil || parsed != f {
+			t.Errorf("Convert(%v) = %s, reads back as %v (%v)", f, s, parsed, err)
+		}
+	}
+}
+
+func TestDigitWriter(t *testing.T) {
+	rng := rand.New(rand.NewSource(44))
+	values := []uint64{0, 9, 10, 99, 100, 99999999, 100000000, 1<<53 + 1, math.MaxUint64}
+	for i := 0; i < 20000; i++ {
+		values = append(values, rng.Uint64()>>uint(rng.Intn(64)))
+	}
+	for _, n := range values {
+		if got, want := string(appendUint64([]byte("x"), n)), "x"+strconv.FormatUint(n, 10); got != want {
+			t.Fatalf("appendUint64(%d) = %s, want %s", n, got, want)
+		}
+		if got, want := countDigits(n), len(strconv.FormatUint(n, 10)); got != want {
+			t.Fatalf("countDigits(%d) = %d, want %d", n, got, want)
+		}
+	}
+
+	// Every 8-digit block, sampled in -short mode
+	step := uint32(1)
+	if testing.Short() {
+		step = 997
+	}
+	for v := uint32(0); v < 1e8; v += step {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], swarDigits8(v))
+		for i, x := 7, v; i >= 0; i, x = i-1, x/10 {
+			if buf[i] != byte('0'+x%10) {
+				t.Fatalf("swarDigits8(%d) = %s", v, buf[:])
+			}
+		}
+	}
+}
+
+func TestAppendDecimalLayouts(t *testing.T) {
+	rng := rand.New(rand.NewSource(45))
+	for i := 0; i < 50000; i++ {
+		nd := 1 + rng.Intn(17)
+		mantissa := uint64Pow10[nd-1] + uint64(rng.Int63n(int64(uint64Pow10[nd]-uint64Pow10[nd-1])))
+		d := Decimal{Mantissa: mantissa, Exponent: int32(rng.Intn(50) - 30), Negative: rng.Intn(2) == 0}
+
+		// Reference layout built from strconv digits
+		digits := strconv.FormatUint(d.Mantissa, 10)
+		dp := len(digits) + int(d.Exponent)
+		var want string
+		switch {
+		case d.Exponent == 0:
+			want = digits
+		case dp > 0 && dp <= len(digits):
+			want = digits[:dp] + "." + digits[dp:]
+		case dp > 0 && dp < len(digits)+4:
+			want = digits + strings.Repeat("0", dp-len(digits))
+		case dp > -4 && dp <= 0:
+			want = "0." + strings.Repeat("0", -dp) + digits
+		default:
+			want = digits[:1]
+			if len(digits) > 1 {
+				want += "." + digits[1:]
+			}
+			want += "e" + strconv.Itoa(dp-1)
+		}
+		if d.Negative {
+			want = "-" + want
+		}
+
+		if got := string(appendDecimal(nil, d)); got != want {
+			t.Fatalf("appendDecimal(%+v) = %s, want %s", d, got, want)
+		}
+	}
+}
+
+// BenchmarkAppendDecimal times the digit writer for each shortest-output
+// length, with the decimal point inside the digits
+func BenchmarkAppendDecimal(b *testing.B) {
+	buf := make([]byte, 0, 32)
+	for nd := 1; nd <= 17; nd++ {
+		d := Decimal{Mantissa: uint64Pow10[nd] / 9, Exponent: -int32(nd / 2)}
+		b.Run(fmt.Sprintf("digits=%d", nd), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = appendDecimal(buf[:0], d)
+			}
+		})
+	}
+}
+
+// BenchmarkAppendUint64 times integer digit emission for each length
+func BenchmarkAppendUint64(b *testing.B) {
+	buf := make([]byte, 0, 32)
+	for nd := 1; nd <= 17; nd++ {
+		n := uint64Pow10[nd] / 9
+		b.Run(fmt.Sprintf("digits=%d", nd), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = appendUint64(buf[:0], n)
+			}
+		})
+	}
+}
+
+func TestAppendFloat(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	values := append(generateMixed(5000), 0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.NaN(),
+		math.MaxFloat64, -math.SmallestNonzeroFloat64, -9.5, 1e15, 1e16, 0.1, 0.01)
+
+	buf := make([]byte, 0, 64)
+	for _, f := range values {
+		if got, want := string(AppendFloat(buf[:0], f)), db.Convert(f); got != want {
+			t.Errorf("AppendFloat(%v) = %s, want %s", f, got, want)
+		}
+	}
+
+	// Appends after existing content
+	if got := string(AppendFloat([]byte("x="), 2.5)); got != "x=2.5" {
+		t.Errorf("AppendFloat onto prefix = %s, want x=2.5", got)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, f := range values {
+			buf = AppendFloat(buf[:0], f)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("AppendFloat allocated %.1f times per run, want 0", allocs)
+	}
+}
+
+func TestWriteFloats(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	values := generateMixed(20000)
+
+	var out strings.Builder
+	if err := WriteFloats(&out, values, ','); err != nil {
+		t.Fatal(err)
+	}
+	want := make([]string, len(values))
+	for i, f := range values {
+		want[i] = db.Convert(f)
+	}
+	if joined := strings.Join(want, ","); out.String() != joined {
+		t.Errorf("WriteFloats output differs from Convert (%d vs %d bytes)", out.Len(), len(joined))
+	}
+
+	out.Reset()
+	if err := WriteFloats(&out, nil, ','); err != nil || out.Len() != 0 {
+		t.Errorf("WriteFloats(nil) wrote %q (%v)", out.String(), err)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		WriteFloats(io.Discard, values, '\n')
+	})
+	if allocs != 0 {
+		t.Errorf("WriteFloats allocated %.1f times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkAppendFloat(b *testing.B) {
+	values := generateMixed(1000)
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = AppendFloat(buf[:0], values[i%len(values)])
+	}
+}
+
+func BenchmarkWriteFloats(b *testing.B) {
+	values := generateMixed(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WriteFloats(io.Discard, values, ',')
+	}
+}
+
+func TestBatchConvertInto(t *testing.T) {
+	values := append(generateMixed(20000), generateScientific(5000)...)
+	values = append(values, 0, math.Copysign(0, -1), math.Inf(1), math.NaN(), -0.1, 1e300)
+	want := NewUnifiedDragonbox().BatchConvert(values)
+
+	check := func(name string, buf []byte, offsets []int32, skip int) {
+		t.Helper()
+		if len(offsets) != skip+len(values)+1 {
+			t.Fatalf("%s: %d offsets, want %d", name, len(offsets), skip+len(values)+1)
+		}
+		if int(offsets[len(offsets)-1]) != len(buf) {
+			t.Errorf("%s: last offset %d, arena is %d bytes", name, offsets[len(offsets)-1], len(buf))
+		}
+		for i := range values {
+			if got := string(buf[offsets[skip+i]:offsets[skip+i+1]]); got != want[i] {
+				t.Fatalf("%s[%d] = %s, want %s", name, i, got, want[i])
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		convert func([]float64, []byte, []int32) ([]byte, []int32, error)
+	}{
+		{"BatchConvertInto", NewUnifiedDragonbox().BatchConvertInto},
+		{"BatchConvertIntoParallel", NewUnifiedDragonbox().BatchConvertIntoParallel},
+		{"BatchConvertIntoParallel/NoCache", NewUnifiedDragonboxWithCache(FormatOptions{}, NoCache()).BatchConvertIntoParallel},
+	} {
+		buf, offsets, err := tc.convert(values, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		check(tc.name, buf, offsets, 0)
+
+		// Appending keeps what is already there
+		buf, offsets, err = tc.convert(values, []byte("prefix"), []int32{0})
+		if err != nil || string(buf[:6]) != "prefix" || offsets[0] != 0 || offsets[1] != 6 {
+			t.Fatalf("%s onto prefix: %q, %v, %v", tc.name, buf[:min(len(buf), 6)], offsets[:2], err)
+		}
+		check(tc.name+" onto prefix", buf, offsets, 1)
+
+		// Small batches and empty ones
+		buf, offsets, err = tc.convert(values[:3], buf[:0], offsets[:0])
+		if err != nil || len(offsets) != 4 || string(buf) != want[0]+want[1]+want[2] {
+			t.Errorf("%s(3 values) = %q, %v, %v", tc.name, buf, offsets, err)
+		}
+		if buf, offsets, err = tc.convert(nil, buf[:0], offsets[:0]); err != nil || len(buf) != 0 || len(offsets) != 1 || offsets[0] != 0 {
+			t.Errorf("%s(nil) = %q, %v, %v", tc.name, buf, offsets, err)
+		}
+	}
+
+	// Rejected values stay empty and the error names the first of them
+	strict := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: DialectJSON})
+	values[700], values[9000] = math.NaN(), math.Inf(1)
+	for _, convert := range []func([]float64, []byte, []int32) ([]byte, []int32, error){strict.BatchConvertInto, strict.BatchConvertIntoParallel} {
+		buf, offsets, err := convert(values, nil, nil)
+		if !errors.Is(err, ErrNonFinite) || !strings.Contains(err.Error(), "element 700") {
+			t.Errorf("error = %v, want element 700 ErrNonFinite", err)
+		}
+		if offsets[700] != offsets[701] || string(buf[offsets[701]:offsets[702]]) != strict.Convert(values[701]) {
+			t.Errorf("rejected element is %q", buf[offsets[700]:offsets[701]])
+		}
+	}
+}
+
+func TestBatchConvertIntoAllocations(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	values := generateMixed(5000)
+	buf, offsets, _ := db.BatchConvertInto(values, nil, nil)
+
+	// The compiler turns append(s, make(...)...) into an in-place extend,
+	// except under -race; allow the make when this toolchain keeps it
+	scratch := make([]int32, 0, len(values)+1)
+	extend := testing.AllocsPerRun(10, func() {
+		scratch = append(scratch[:0], make([]int32, len(values)+1)...)
+	})
+
+	allocs := testing.AllocsPerRun(10, func() {
+		buf, offsets, _ = db.BatchConvertInto(values, buf[:0], offsets[:0])
+	})
+	if allocs > extend {
+		t.Errorf("BatchConvertInto allocated %.1f times per run, want %.0f", allocs, extend)
+	}
+
+	// Goroutines and bookkeeping only; arenas come from the pool
+	buf, offsets, _ = db.BatchConvertIntoParallel(values, buf[:0], offsets[:0])
+	allocs = testing.AllocsPerRun(10, func() {
+		buf, offsets, _ = db.BatchConvertIntoParallel(values, buf[:0], offsets[:0])
+	})
+	if limit := float64(4*db.converter.workers + 8); allocs > limit {
+		t.Errorf("BatchConvertIntoParallel allocated %.1f times per run, want at most %.0f", allocs, limit)
+	}
+}
+
+func BenchmarkBatchConvertInto(b *testing.B) {
+	values := generateMixed(100000)
+
+	b.Run("BatchConvert", func(b *testing.B) {
+		db := NewUnifiedDragonboxWithCache(FormatOptions{}, NoCache())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = db.BatchConvert(values)
+		}
+	})
+	b.Run("Into", func(b *testing.B) {
+		db := NewUnifiedDragonboxWithCache(FormatOptions{}, NoCache())
+		var buf []byte
+		var offsets []int32
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf, offsets, _ = db.BatchConvertInto(values, buf[:0], offsets[:0])
+		}
+	})
+	b.Run("IntoParallel", func(b *testing.B) {
+		db := NewUnifiedDragonboxWithCache(FormatOptions{}, NoCache())
+		var buf []byte
+		var offsets []int32
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf, offsets, _ = db.BatchConvertIntoParallel(values, buf[:0], offsets[:0])
+		}
+	})
+}
+
+func TestDialects(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	tests := []struct {
+		dialect Dialect
+		value   float64
+		want    string
+	}{
+		{DialectDefault, math.Inf(1), "+Inf"},
+		{DialectDefault, -0.25, "-0.25"},
+		{DialectGo, 1e6, "1e+06"},
+		{DialectGo, 1e21, "1e+21"},
+		{DialectGo, math.Inf(1), "+Inf"},
+		{DialectJSON, 1e21, "1e+21"},
+		{DialectJSON, 1e20, "100000000000000000000"},
+		{DialectJSON, 1e-7, "1e-7"},
+		{DialectJSON, 0.000001, "0.000001"},
+		{DialectJSON, negZero, "-0"},
+		{DialectJavaScript, 123e-20, "1.23e-18"},
+		{DialectJavaScript, 1.5e300, "1.5e+300"},
+		{DialectJavaScript, -0.1, "-0.1"},
+		{DialectJavaScript, negZero, "0"},
+		{DialectJavaScript, math.Inf(-1), "-Infinity"},
+		{DialectJavaScript, math.NaN(), "NaN"},
+		{DialectPython, 1, "1.0"},
+		{DialectPython, 1e15, "1000000000000000.0"},
+		{DialectPython, 1e16, "1e+16"},
+		{DialectPython, 1e22, "1e+22"},
+		{DialectPython, 0.0001, "0.0001"},
+		{DialectPython, 0.00001, "1e-05"},
+		{DialectPython, -1.5e-300, "-1.5e-300"},
+		{DialectPython, 123.456, "123.456"},
+		{DialectPython, negZero, "-0.0"},
+		{DialectPython, math.Inf(1), "inf"},
+		{DialectPython, math.NaN(), "nan"},
+		{DialectC, 0.1, "0.10000000000000001"},
+		{DialectC, 100, "100"},
+		{DialectC, 1e17, "1e+17"},
+		{DialectC, negZero, "-0"},
+		{DialectC, math.Inf(-1), "-inf"},
+	}
+
+	for _, tt := range tests {
+		db := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: tt.dialect})
+		if got := db.Convert(tt.value); got != tt.want {
+			t.Errorf("%v Convert(%v) = %s, want %s", tt.dialect, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDialectReferences(t *testing.T) {
+	values := append(generateMixed(20000), 1e20, 1e21, 1e-6, 1e-7, 0.001, -0.01, 123456789, 1e15, 1e16)
+	goDialect := FormatOptions{Dialect: DialectGo}
+	cDialect := FormatOptions{Dialect: DialectC}
+	jsonDialect := FormatOptions{Dialect: DialectJSON}
+
+	for _, f := range values {
+		if got, _ := goDialect.AppendFloat(nil, f); string(got) != fmt.Sprintf("%v", f) {
+			t.Errorf("Go dialect %v = %s", f, got)
+		}
+		if got, _ := cDialect.AppendFloat(nil, f); string(got) != strconv.FormatFloat(f, 'g', 17, 64) {
+			t.Errorf("C dialect %v = %s", f, got)
+		}
+		want, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := jsonDialect.AppendFloat(nil, f); string(got) != string(want) {
+			t.Errorf("JSON dialect %v = %s, want %s", f, got, want)
+		}
+	}
+}
+
+func TestDialectBatchMatchesConvert(t *testing.T) {
+	values := append(generateMixed(5000), -0.5, -0.1, -0.001, 0.25, math.Copysign(0, -1), 0,
+		math.Inf(1), math.Inf(-1), math.NaN(), -42, 1e300)
+
+	for _, dialect := range []Dialect{DialectDefault, DialectGo, DialectJSON, DialectJavaScript, DialectPython, DialectC} {
+		options := FormatOptions{Dialect: dialect, NonFinite: NonFiniteNull}
+		batch := NewUnifiedDragonboxWithOptions(options).BatchConvert(values)
+		db := NewUnifiedDragonboxWithOptions(options)
+		for i, f := range values {
+			if want := db.Convert(f); batch[i] != want {
+				t.Errorf("%v: BatchConvert[%d] = %s, Convert(%v) = %s", dialect, i, batch[i], f, want)
+			}
+			if got, _ := options.AppendFloat(nil, f); string(got) != batch[i] {
+				t.Errorf("%v: AppendFloat(%v) = %s, BatchConvert gave %s", dialect, f, got, batch[i])
+			}
+		}
+	}
+}
+
+func TestDialectsNarrow(t *testing.T) {
+	negZero := float32(math.Copysign(0, -1))
+	tests := []struct {
+		dialect Dialect
+		value   float32
+		want    string
+	}{
+		{DialectGo, 1e6, "1e+06"},
+		{DialectGo, 0.1, "0.1"},
+		{DialectJSON, 1e-7, "1e-7"},
+		{DialectJavaScript, negZero, "0"},
+		{DialectJavaScript, float32(math.Inf(-1)), "-Infinity"},
+		{DialectPython, 1, "1.0"},
+		{DialectPython, -0.5, "-0.5"},
+		{DialectPython, 1e-5, "1e-05"},
+		{DialectPython, float32(math.NaN()), "nan"},
+		{DialectC, 0.1, "0.10000000149011612"},
+	}
+
+	for _, tt := range tests {
+		db := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: tt.dialect})
+		if got := db.ConvertFloat32(tt.value); got != tt.want {
+			t.Errorf("%v ConvertFloat32(%v) = %s, want %s", tt.dialect, tt.value, got, tt.want)
+		}
+	}
+
+	python := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: DialectPython})
+	if got := python.ConvertFloat16(0x3C00); got != "1.0" {
+		t.Errorf("Python ConvertFloat16(1) = %s, want 1.0", got)
+	}
+	if got := python.ConvertBFloat16(0xBF00); got != "-0.5" {
+		t.Errorf("Python ConvertBFloat16(-0.5) = %s, want -0.5", got)
+	}
+}
+
+// TestDialectsNarrowMatchConvert checks that a narrow value prints like the
+// float64 that its shortest digits parse to, in every dialect
+func TestDialectsNarrowMatchConvert(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	floats := make([]float32, 2000)
+	for i := range floats {
+		floats[i] = math.Float32frombits(rng.Uint32())
+	}
+	floats = append(floats, 0.5, -0.1, 0.01, 0.001, 42, -16777216, 1e10, 3.4028235e38, 1e-45,
+		float32(math.Copysign(0, -1)), 0, float32(math.Inf(1)), float32(math.NaN()))
+	halves := make([]uint16, 1<<16)
+	for i := range halves {
+		halves[i] = uint16(i)
+	}
+
+	plain := NewUnifiedDragonbox()
+	for _, dialect := range []Dialect{DialectDefault, DialectGo, DialectJSON, DialectJavaScript, DialectPython, DialectC} {
+		options := FormatOptions{Dialect: dialect, NonFinite: NonFiniteNull}
+		db := NewUnifiedDragonboxWithOptions(options)
+
+		// want gives the expected text for value, whose shortest digits at
+		// its own width are digits and whose fast-path integer limit is limit
+		want := func(value float64, digits string, limit float64) (string, bool) {
+			if dialect == DialectC {
+				return db.Convert(value), true
+			}
+			parsed, err := strconv.ParseFloat(digits, 64)
+			if err != nil {
+				parsed = value
+			}
+			// Integers past the fast path print their shortest digits,
+			// which a float64 would spell out in full
+			if parsed == math.Trunc(parsed) && math.Abs(parsed) > limit {
+				return "", false
+			}
+			return db.Convert(parsed), true
+		}
+
+		batch := db.BatchConvertFloat32(floats)
+		for i, f := range floats {
+			got := db.ConvertFloat32(f)
+			if batch[i] != got {
+				t.Errorf("%v: BatchConvertFloat32[%d] = %s, ConvertFloat32 = %s", dialect, i, batch[i], got)
+			}
+			expected, ok := strconv.FormatFloat(float64(f), 'g', -1, 32), true
+			if dialect != DialectGo {
+				expected, ok = want(float64(f), plain.ConvertFloat32(f), 1<<24)
+			}
+			if ok && got != expected {
+				t.Errorf("%v: ConvertFloat32(%v) = %s, want %s", dialect, f, got, expected)
+			}
+		}
+
+		for _, tc := range []struct {
+			name    string
+			hf      *halfFormat
+			convert func(uint16) string
+			batch   func([]uint16) []string
+			plain   func(uint16) string
+		}{
+			{"Float16", float16Format, db.ConvertFloat16, db.BatchConvertFloat16, plain.ConvertFloat16},
+			{"BFloat16", bfloat16Format, db.ConvertBFloat16, db.BatchConvertBFloat16, plain.ConvertBFloat16},
+		} {
+			batch := tc.batch(halves)
+			limit := float64(uint64(1) << (tc.hf.significandBits + 1))
+			for _, h := range halves {
+				got := tc.convert(h)
+				if batch[h] != got {
+					t.Errorf("%v: BatchConvert%s[%#04x] = %s, Convert%s = %s", dialect, tc.name, h, batch[h], tc.name, got)
+				}
+				if dialect == DialectGo {
+					continue
+				}
+				if expected, ok := want(tc.hf.toFloat64(h), tc.plain(h), limit); ok && got != expected {
+					t.Errorf("%v: Convert%s(%#04x) = %s, want %s", dialect, tc.name, h, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestJSONNonFiniteNarrow(t *testing.T) {
+	strict := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: DialectJSON})
+	for _, f := range []float32{float32(math.NaN()), float32(math.Inf(1)), float32(math.Inf(-1))} {
+		if s, err := strict.ConvertFloat32Checked(f); !errors.Is(err, ErrNonFinite) || s != "" {
+			t.Errorf("ConvertFloat32Checked(%v) = %q, %v; want ErrNonFinite", f, s, err)
+		}
+	}
+	if s, err := strict.ConvertFloat16Checked(0x7C00); !errors.Is(err, ErrNonFinite) || s != "" {
+		t.Errorf("ConvertFloat16Checked(+Inf) = %q, %v; want ErrNonFinite", s, err)
+	}
+	if s, err := strict.ConvertBFloat16Checked(0x7FC0); !errors.Is(err, ErrNonFinite) || s != "" {
+		t.Errorf("ConvertBFloat16Checked(NaN) = %q, %v; want ErrNonFinite", s, err)
+	}
+
+	// The reported index is the first failure even across workers
+	floats := make([]float32, 1000)
+	halves := make([]uint16, 1000)
+	for i := range floats {
+		floats[i] = float32(i) * 0.25
+		halves[i] = uint16(i)
+	}
+	floats[700] = float32(math.NaN())
+	floats[350] = float32(math.Inf(1))
+	halves[700] = 0x7E00
+	halves[350] = 0xFC00
+	if _, err := strict.BatchConvertFloat32Checked(floats); !errors.Is(err, ErrNonFinite) || !strings.Contains(err.Error(), "element 350") {
+		t.Errorf("BatchConvertFloat32Checked error = %v, want ErrNonFinite at element 350", err)
+	}
+	if _, err := strict.BatchConvertFloat16Checked(halves); !errors.Is(err, ErrNonFinite) || !strings.Contains(err.Error(), "element 350") {
+		t.Errorf("BatchConvertFloat16Checked error = %v, want ErrNonFinite at element 350", err)
+	}
+	results, err := strict.BatchConvertBFloat16Checked(halves[:300])
+	if err != nil || results[128] != strict.ConvertBFloat16(128) {
+		t.Errorf("BatchConvertBFloat16Checked = %v, %v", results[128], err)
+	}
+}
+
+func TestJSONNonFinite(t *testing.T) {
+	strict := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: DialectJSON})
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if s, err := strict.ConvertChecked(f); !errors.Is(err, ErrNonFinite) || s != "" {
+			t.Errorf("ConvertChecked(%v) = %q, %v; want ErrNonFinite", f, s, err)
+		}
+	}
+
+	lenient := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: DialectJSON, NonFinite: NonFiniteNull})
+	if s, err := lenient.ConvertChecked(math.Inf(-1)); err != nil || s != "null" {
+		t.Errorf("null policy gave %q, %v", s, err)
+	}
+
+	named := NewUnifiedDragonboxWithOptions(FormatOptions{Dialect: DialectJSON, NonFinite: NonFiniteString})
+	if s, err := named.ConvertChecked(math.Inf(-1)); err != nil || s != `"-Infinity"` {
+		t.Errorf("string policy gave %q, %v", s, err)
+	}
+
+	// The reported index is the first failure even across workers
+	values := generateMixed(1000)
+	values[700] = math.NaN()
+	values[350] = math.Inf(1)
+	results, err := strict.BatchConvertChecked(values)
+	if !errors.Is(err, ErrNonFinite) || !strings.Contains(err.Error(), "element 350") {
+		t.Errorf("BatchConvertChecked error = %v, want ErrNonFinite at element 350", err)
+	}
+	if len(results) != len(values) || results[0] != strict.Convert(values[0]) {
+		t.Errorf("BatchConvertChecked did not convert the finite values")
+	}
+}
+
+func TestJSONTypes(t *testing.T) {
+	var (
+		_ json.Marshaler           = Float64(0)
+		_ json.Unmarshaler         = (*Float64)(nil)
+		_ encoding.TextMarshaler   = Float64(0)
+		_ encoding.TextUnmarshaler = (*Float64)(nil)
+		_ json.Marshaler           = Float32(0)
+		_ json.Unmarshaler         = (*Float32)(nil)
+		_ encoding.TextMarshaler   = Float32(0)
+		_ encoding.TextUnmarshaler = (*Float32)(nil)
+		_ json.Marshaler           = Float64s(nil)
+		_ json.Unmarshaler         = (*Float64s)(nil)
+	)
+
+	// encoding/json's own float output is the reference
+	values := append(generateMixed(5000), generateScientific(5000)...)
+	values = append(values, 0, math.Copysign(0, -1), 1e21, 1e-7, 123456789012345680000, math.MaxFloat64, math.SmallestNonzeroFloat64)
+	for _, f := range values {
+		got, err := json.Marshal(Float64(f))
+		want, _ := json.Marshal(f)
+		if err != nil || string(got) != string(want) {
+			t.Fatalf("Marshal(Float64(%v)) = %s, %v; want %s", f, got, err, want)
+		}
+		if math.IsInf(float64(float32(f)), 0) {
+			continue
+		}
+		got, err = json.Marshal(Float32(f))
+		want, _ = json.Marshal(float32(f))
+		if err != nil || string(got) != string(want) {
+			t.Fatalf("Marshal(Float32(%v)) = %s, %v; want %s", float32(f), got, err, want)
+		}
+	}
+
+	// Struct fields, pointers and map keys need no custom encoder
+	type point struct {
+		X     Float64          `json:"x"`
+		Y     *Float32         `json:"y"`
+		Trace Float64s         `json:"trace"`
+		Tags  map[Float64]bool `json:"tags"`
+	}
+	y := Float32(0.1)
+	in := point{X: 0.3, Y: &y, Trace: Float64s{1, 0.5, -2.5e-7}, Tags: map[Float64]bool{1.5: true}}
+	out, err := json.Marshal(in)
+	if want := `{"x":0.3,"y":0.1,"trace":[1,0.5,-2.5e-7],"tags":{"1.5":true}}`; err != nil || string(out) != want {
+		t.Fatalf("Marshal(point) = %s, %v; want %s", out, err, want)
+	}
+	var back point
+	if err := json.Unmarshal(out, &back); err != nil || back.X != in.X || *back.Y != y || len(back.Trace) != 3 || back.Trace[2] != -2.5e-7 || !back.Tags[1.5] {
+		t.Fatalf("Unmarshal(%s) = %+v, %v", out, back, err)
+	}
+
+	// Text forms match Convert and ConvertFloat32
+	db := NewUnifiedDragonbox()
+	for _, f := range []float64{0.1, 1e21, -0.0, math.NaN(), math.Inf(-1), 16777216, 1.0 / 3} {
+		if text, _ := Float64(f).MarshalText(); string(text) != db.Convert(f) {
+			t.Errorf("Float64(%v).MarshalText() = %s, want %s", f, text, db.Convert(f))
+		}
+		if text, _ := Float32(f).MarshalText(); string(text) != db.ConvertFloat32(float32(f)) {
+			t.Errorf("Float32(%v).MarshalText() = %s, want %s", f, text, db.ConvertFloat32(float32(f)))
+		}
+	}
+}
+
+func TestJSONTypesNonFinite(t *testing.T) {
+	defer func(policy NonFinitePolicy) { JSONNonFinite = policy }(JSONNonFinite)
+
+	JSONNonFinite = NonFiniteError
+	for _, v := range []interface{}{Float64(math.NaN()), Float32(math.Inf(1)), Float64s{1, math.Inf(-1)}} {
+		if out, err := json.Marshal(v); !errors.Is(err, ErrNonFinite) {
+			t.Errorf("Marshal(%v) = %s, %v; want ErrNonFinite", v, out, err)
+		}
+	}
+
+	JSONNonFinite = NonFiniteNull
+	out, err := json.Marshal([]interface{}{Float64(math.NaN()), Float32(math.Inf(1)), Float64s{1, math.Inf(-1)}})
+	if want := `[null,null,[1,null]]`; err != nil || string(out) != want {
+		t.Errorf("null policy: %s, %v; want %s", out, err, want)
+	}
+
+	JSONNonFinite = NonFiniteString
+	out, err = json.Marshal([]interface{}{Float64(math.NaN()), Float32(math.Inf(1)), Float64s{1, math.Inf(-1)}})
+	if want := `["NaN","Infinity",[1,"-Infinity"]]`; err != nil || string(out) != want {
+		t.Errorf("string policy: %s, %v; want %s", out, err, want)
+	}
+
+	// Named non-finite values read back whatever the policy
+	var x Float64
+	var y Float32
+	var xs Float64s
+	if err := json.Unmarshal([]byte(`"-Infinity"`), &x); err != nil || !math.IsInf(float64(x), -1) {
+		t.Errorf("Unmarshal -Infinity = %v, %v", x, err)
+	}
+	if err := json.Unmarshal([]byte(`"NaN"`), &y); err != nil || !math.IsNaN(float64(y)) {
+		t.Errorf("Unmarshal NaN = %v, %v", y, err)
+	}
+	if err := json.Unmarshal([]byte(` [ "Infinity" , null,2 ] `), &xs); err != nil || len(xs) != 3 || !math.IsInf(xs[0], 1) || xs[1] != 0 || xs[2] != 2 {
+		t.Errorf("Unmarshal array = %v, %v", xs, err)
+	}
+
+	// null leaves scalars alone; quoted numbers are not numbers
+	x = 7
+	if err := json.Unmarshal([]byte(`null`), &x); err != nil || x != 7 {
+		t.Errorf("Unmarshal null = %v, %v", x, err)
+	}
+	for _, input := range []string{`"1.5"`, `true`, `{}`} {
+		if err := json.Unmarshal([]byte(input), &x); err == nil {
+			t.Errorf("Unmarshal(%s) into Float64 succeeded", input)
+		}
+	}
+	for _, input := range []string{`"1.5"`, `[1,"2"]`, `[[1]]`, `[{"a":1}]`, `{}`, `1`} {
+		if err := json.Unmarshal([]byte(input), &xs); err == nil {
+			t.Errorf("Unmarshal(%s) into Float64s succeeded", input)
+		}
+	}
+}
+
+func TestJSONTypesParse(t *testing.T) {
+	// Float32 rounds the decimal once; float64 first would tie to even
+	for _, tt := range []struct {
+		input string
+		want  float32
+	}{
+		{"16777217", 16777216},
+		{"16777217.000000000001", 16777218},
+		{"16777218.999999999999", 16777218},
+		{"16777219", 16777220},
+		{"3.4028235677973366e38", math.MaxFloat32},
+		{"-3.40282356779733661637539395458142568447e38", -math.MaxFloat32},
+		{"7.006492321624085354618647916449580656401e-46", 0},
+		{"7.0064923216240853546186479164495806564014e-46", 0x1p-149},
+	} {
+		var got Float32
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil || float32(got) != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+		if want, _ := strconv.ParseFloat(tt.input, 32); float32(want) != tt.want {
+			t.Errorf("test case %s: strconv gives %v", tt.input, float32(want))
+		}
+	}
+	var y Float32
+	if err := y.UnmarshalText([]byte("3.4028236e38")); !errors.Is(err, ErrRange) {
+		t.Errorf("UnmarshalText overflow error = %v, want ErrRange", err)
+	}
+
+	// Round trips and a large batch through Float64s
+	rng := rand.New(rand.NewSource(1))
+	values := make(Float64s, 20000)
+	for i := range values {
+		values[i] = math.Float64frombits(rng.Uint64() &^ (ExponentMask << SignificandBits))
+		if i%3 == 0 {
+			values[i] = float64(math.Float32frombits(rng.Uint32() &^ (Float32ExponentMask << Float32SignificandBits)))
+		}
+	}
+	out, err := json.Marshal(values)
+	want, _ := json.Marshal([]float64(values))
+	if err != nil || string(out) != string(want) {
+		t.Fatalf("Marshal(Float64s) differs from encoding/json: %v", err)
+	}
+	var back Float64s
+	if err := json.Unmarshal(out, &back); err != nil || len(back) != len(values) {
+		t.Fatalf("Unmarshal(Float64s) = %d values, %v", len(back), err)
+	}
+	for i := range values {
+		if back[i] != values[i] {
+			t.Fatalf("Float64s[%d] = %v, want %v", i, back[i], values[i])
+		}
+		var x32 Float32
+		if i%3 == 0 && (json.Unmarshal(strconv.AppendFloat(nil, values[i], 'g', -1, 32), &x32) != nil || float64(x32) != values[i]) {
+			t.Fatalf("Float32 round trip of %v = %v", values[i], x32)
+		}
+	}
+
+	for _, empty := range []Float64s{nil, {}} {
+		out, _ := json.Marshal(empty)
+		if want, _ := json.Marshal([]float64(empty)); string(out) != string(want) {
+			t.Errorf("Marshal(%#v) = %s, want %s", empty, out, want)
+		}
+	}
+}
+
+func BenchmarkJSONTypes(b *testing.B) {
+	values := generateMixed(10000)
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal(values)
+		}
+	})
+	b.Run("Float64s", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = json.Marshal(Float64s(values))
+		}
+	})
+}
+
+func tableFixture(rows int) []Column {
+	floats := generateMixed(rows)
+	ints := make([]int, rows)
+	strs := make([]string, rows)
+	for i := range ints {
+		ints[i] = i*7919 - rows
+		strs[i] = fmt.Sprintf("r%d", i)
+	}
+	strs[0] = `quote " and, comma`
+	strs[rows-1] = "line\nbreak\t \x01"
+	return []Column{FloatColumn("value", floats), IntColumn("id", ints), StringColumn("label, text", strs)}
+}
+
+func TestTableEncoderCSV(t *testing.T) {
+	const rows = 5000
+	columns := tableFixture(rows)
+	db := NewUnifiedDragonbox()
+
+	for _, workers := range []int{1, 4} {
+		var out strings.Builder
+		enc := NewTableEncoder(&out, TableCSV)
+		enc.workers, enc.buffers = workers, make([][]byte, workers)
+		if err := enc.Encode(columns...); err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+		if err != nil {
+			t.Fatalf("workers %d: output is not CSV: %v", workers, err)
+		}
+		if len(records) != rows+1 || strings.Join(records[0], "|") != "value|id|label, text" {
+			t.Fatalf("workers %d: %d records, header %q", workers, len(records), records[0])
+		}
+		for i, rec := range records[1:] {
+			want := []string{db.Convert(columns[0].floats[i]), strconv.Itoa(columns[1].ints[i]), columns[2].strings[i]}
+			if strings.Join(rec, "|") != strings.Join(want, "|") {
+				t.Fatalf("workers %d row %d = %q, want %q", workers, i, rec, want)
+			}
+		}
+	}
+}
+
+func TestTableEncoderJSON(t *testing.T) {
+	const rows = 3000
+	columns := tableFixture(rows)
+
+	var out strings.Builder
+	enc := NewTableEncoder(&out, TableJSON)
+	enc.workers, enc.buffers = 3, make([][]byte, 3)
+	if err := enc.Encode(columns...); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if len(decoded) != rows {
+		t.Fatalf("decoded %d rows, want %d", len(decoded), rows)
+	}
+	for i, row := range decoded {
+		if row["value"] != columns[0].floats[i] || row["id"] != float64(columns[1].ints[i]) || row["label, text"] != columns[2].strings[i] {
+			t.Fatalf("row %d = %v", i, row)
+		}
+	}
+
+	// String escaping matches encoding/json apart from HTML escapes
+	for _, s := range []string{"plain", "a\"b\\c", "\x00\x1f", "  ", "bad\xffutf8", "é世"} {
+		want, _ := json.Marshal(s)
+		if got := appendJSONString(nil, s); string(got) != string(want) {
+			t.Errorf("appendJSONString(%q) = %s, want %s", s, got, want)
+		}
+	}
+
+	out.Reset()
+	if err := NewTableEncoder(&out, TableJSON).Encode(FloatColumn("x", nil)); err != nil || out.String() != "[]\n" {
+		t.Errorf("empty table = %q, %v", out.String(), err)
+	}
+}
+
+func TestTableEncoderErrors(t *testing.T) {
+	err := NewTableEncoder(io.Discard, TableCSV).Encode(FloatColumn("a", []float64{1, 2}), IntColumn("b", []int{1}))
+	if err == nil || !strings.Contains(err.Error(), `"b"`) {
+		t.Errorf("mismatched columns error = %v", err)
+	}
+
+	values := generateMixed(2000)
+	values[1234] = math.NaN()
+	err = NewTableEncoder(io.Discard, TableJSON).Encode(FloatColumn("x", values))
+	if !errors.Is(err, ErrNonFinite) || !strings.Contains(err.Error(), "row 1234") {
+		t.Errorf("NaN error = %v, want ErrNonFinite at row 1234", err)
+	}
+
+	var out strings.Builder
+	enc := NewTableEncoderWithOptions(&out, TableJSON, FormatOptions{NonFinite: NonFiniteNull})
+	if err := enc.Encode(FloatColumn("x", []float64{math.Inf(1), 0.5})); err != nil || out.String() != "[\n{\"x\":null},\n{\"x\":0.5}\n]\n" {
+		t.Errorf("null policy output %q, %v", out.String(), err)
+	}
+}
+
+func BenchmarkTableEncoder(b *testing.B) {
+	columns := tableFixture(100000)
+	enc := NewTableEncoder(io.Discard, TableCSV)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := enc.Encode(columns...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	formats := []byte{'e', 'E', 'f', 'g', 'G'}
+	precisions := []int{-1, 0, 1, 2, 3, 5, 8, 12, 15, 16, 17, 18, 19, 20, 25, 40, 100, 340, 800}
+
+	// Ties, carries, extremes and the 'g' notation switch points
+	values := []float64{
+		0, math.Copysign(0, -1), 0.125, 0.375, 2.5, 3.5, -0.5, 1.5, 9.9995, 999.9996,
+		0.0009, 0.0005, 0.00051, 1e21, 1e20, 123456789, 1e-4, 1e-5, 100000, 1000000,
+		5e-324, math.SmallestNonzeroFloat64 * 3, 0x1p-1022, math.MaxFloat64, 1e23,
+		math.Pi, -math.E, 1.0 / 3, 2.0 / 3, 0.1, 0.3, 1e-300, 1e300,
+		math.Inf(1), math.Inf(-1), math.NaN(),
+	}
+	for _, f := range values {
+		for _, fmt := range formats {
+			for _, prec := range precisions {
+				if got, want := db.Format(f, fmt, prec), strconv.FormatFloat(f, fmt, prec, 64); got != want {
+					t.Errorf("Format(%v, %c, %d) = %s, want %s", f, fmt, prec, got, want)
+				}
+			}
+		}
+	}
+
+	// Random bit patterns and report-style magnitudes
+	samples := int(*differentialSamples / 8)
+	if testing.Short() {
+		samples = 1 << 12
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	failures := 0
+	for i := 0; i < samples && failures < 10; i++ {
+		f := math.Float64frombits(rng.Uint64())
+		if i%2 == 1 {
+			f = (rng.Float64() - 0.5) * math.Pow10(rng.Intn(12))
+		}
+		fmt := formats[rng.Intn(len(formats))]
+		prec := rng.Intn(25) - 1
+		if got, want := AppendFormat(nil, f, fmt, prec), strconv.FormatFloat(f, fmt, prec, 64); string(got) != want {
+			failures++
+			t.Errorf("Format(%b, %c, %d) = %s, want %s", f, fmt, prec, got, want)
+		}
+	}
+
+	if got := db.Format(1, 'q', 2); got != "%q" {
+		t.Errorf("Format with unknown verb = %s, want %%q", got)
+	}
+}
+
+func TestFixedPrecisionPaths(t *testing.T) {
+	// The 128-bit table path must agree with the exact expansion
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 100000; i++ {
+		bits := rng.Uint64() &^ SignMask
+		if (bits>>SignificandBits)&ExponentMask == ExponentMask {
+			continue
+		}
+		mantissa, exponent := decompose(bits)
+		digits := 1 + rng.Intn(MaxFixedDigits)
+
+		var buf [24]byte
+		fast := fixedPrecisionDigits(buf[:0], mantissa, exponent, 'g', digits)
+		exact := exactPrecisionDigits(mantissa, exponent, 'g', digits)
+		if string(fast.d[:fast.nd]) != string(exact.d[:exact.nd]) || fast.dp != exact.dp {
+			t.Fatalf("%#016x at %d digits: table %s/%d, exact %s/%d", bits, digits,
+				fast.d[:fast.nd], fast.dp, exact.d[:exact.nd], exact.dp)
+		}
+	}
+}
+
+func BenchmarkFormatFixed(b *testing.B) {
+	values := generateDecimals(1000)
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = AppendFormat(buf[:0], values[i%len(values)], 'f', 3)
+	}
+}
+
+func TestFormatHex(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{3, "0x1.8p+1"},
+		{1, "0x1p+0"},
+		{0.1, "0x1.999999999999ap-4"},
+		{-0.5, "-0x1p-1"},
+		{0, "0x0p+0"},
+		{math.Copysign(0, -1), "-0x0p+0"},
+		{math.MaxFloat64, "0x1.fffffffffffffp+1023"},
+		{math.SmallestNonzeroFloat64, "0x0.0000000000001p-1022"},
+		{0x1p-1022, "0x1p-1022"},
+		{0x1.8p-1023, "0x0.cp-1022"},
+		{math.Inf(1), "inf"},
+		{math.Inf(-1), "-inf"},
+		{math.NaN(), "nan"},
+	}
+	for _, tt := range tests {
+		if got := FormatHex(tt.value); got != tt.want {
+			t.Errorf("FormatHex(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(47))
+	for i := 0; i < 100000; i++ {
+		f := math.Float64frombits(rng.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		hex := FormatHex(f)
+		if back, err := strconv.ParseFloat(hex, 64); err != nil || back != f {
+			t.Fatalf("FormatHex(%v) = %s reads back as %v, %v", f, hex, back, err)
+		}
+
+		// Normal numbers match strconv apart from its two-digit exponent
+		if math.Abs(f) >= 0x1p-1022 {
+			want := strconv.FormatFloat(f, 'x', -1, 64)
+			want = strings.Replace(strings.Replace(want, "p+0", "p+", 1), "p-0", "p-", 1)
+			if hex != want {
+				t.Fatalf("FormatHex(%v) = %s, want %s", f, hex, want)
+			}
+		}
+	}
+}
+
+func TestFormatExact(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0.1, "0.1000000000000000055511151231257827021181583404541015625"},
+		{-2.5, "-2.5"},
+		{1 << 70, "1180591620717411303424"},
+		{1e23, "99999999999999991611392"},
+		{0, "0"},
+		{math.Copysign(0, -1), "-0"},
+		{math.Inf(1), "+Inf"},
+		{math.NaN(), "NaN"},
+	}
+	for _, tt := range tests {
+		if got := FormatExact(tt.value); got != tt.want {
+			t.Errorf("FormatExact(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	if s := FormatExact(math.SmallestNonzeroFloat64); !strings.HasPrefix(s, "0.000") || len(s) != 2+1074 || !strings.Contains(s, "04940656458412465441765687928682213723650598026143247644255856825") || !strings.HasSuffix(s, "33447265625") {
+		t.Errorf("FormatExact(SmallestNonzeroFloat64) has %d characters: %s", len(s), s)
+	}
+
+	rng := rand.New(rand.NewSource(48))
+	values := []float64{math.MaxFloat64, 0x1p-1022, 123.456, 1e-5}
+	for i := 0; i < 3000; i++ {
+		values = append(values, math.Float64frombits(rng.Uint64()), math.Ldexp(float64(rng.Int63n(1<<53)), rng.Intn(140)-100))
+	}
+	for _, f := range values {
+		if math.IsNaN(f) || math.IsInf(f, 0) || f == 0 {
+			continue
+		}
+		want := new(big.Rat).SetFloat64(f).FloatString(1074)
+		want = strings.TrimRight(strings.TrimRight(want, "0"), ".")
+		if got := FormatExact(f); got != want {
+			t.Fatalf("FormatExact(%v) = %s, want %s", f, got, want)
+		}
+	}
+}
+
+func TestLocaleFormatter(t *testing.T) {
+	swiss := LocaleGerman
+	swiss.Group, swiss.Decimal, swiss.Minus = "'", ".", "−"
+
+	tests := []struct {
+		locale Locale
+		scale  NumberScale
+		value  float64
+		fmt    byte // 0 for shortest
+		prec   int
+		want   string
+	}{
+		{LocaleEnglish, ScaleNone, 1234567.891, 0, 0, "1,234,567.891"},
+		{LocaleGerman, ScaleNone, 1234567.891, 0, 0, "1.234.567,891"},
+		{LocaleFrench, ScaleNone, -1234567.891, 0, 0, "-1 234 567,891"},
+		{LocaleIndian, ScaleNone, 123456789, 0, 0, "12,34,56,789"},
+		{LocaleIndian, ScaleNone, 1234.5, 0, 0, "1,234.5"},
+		{LocaleEnglish, ScaleNone, 999, 0, 0, "999"},
+		{LocaleEnglish, ScaleNone, math.Copysign(0, -1), 0, 0, "0"},
+		{LocaleEnglish, ScaleNone, 1e21, 0, 0, "1e+21"},
+		{LocaleGerman, ScaleNone, 1.5e-7, 0, 0, "1,5E-7"},
+		{swiss, ScaleNone, -1234.5, 0, 0, "−1'234.5"},
+		{swiss, ScaleNone, 2.5e-300, 0, 0, "2.5E−300"},
+		{LocaleEnglish, ScalePercent, 0.07, 0, 0, "7%"},
+		{LocaleGerman, ScalePercent, 0.125, 0, 0, "12,5 %"},
+		{LocaleEnglish, ScalePerMille, 0.0015, 0, 0, "1.5‰"},
+		{LocaleEnglish, ScalePercent, 12345.678, 0, 0, "1,234,567.8%"},
+		{LocaleGerman, ScaleNone, 1234.5678, 'f', 2, "1.234,57"},
+		{LocaleEnglish, ScaleNone, 1e6, 'f', 0, "1,000,000"},
+		{LocaleEnglish, ScalePercent, 0.12345, 'f', 1, "12.3%"},
+		{LocaleEnglish, ScalePercent, 0.005, 'f', 0, "1%"}, // 0.005 is 0.0050000000000000001
+		{LocaleEnglish, ScalePercent, 0.015, 'f', 0, "1%"}, // 0.015 is 0.0149999999999999999
+		{LocaleEnglish, ScalePercent, 0.125, 'f', 0, "12%"},
+		{LocaleEnglish, ScalePercent, 0.375, 'f', 0, "38%"},
+		{LocaleEnglish, ScalePercent, 0.000123456, 'e', 3, "1.235e-02%"},
+		{LocaleGerman, ScaleNone, 0.000123456, 'E', 3, "1,235E-04"},
+		{LocaleEnglish, ScaleNone, 123456789, 'g', 4, "1.235e+08"},
+		{LocaleIndian, ScaleNone, 12345678.9, 'G', 10, "1,23,45,678.9"},
+		{LocaleEnglish, ScalePerMille, 0.1234, 'g', 3, "123‰"},
+		{LocaleEnglish, ScaleNone, math.Inf(-1), 0, 0, "-∞"},
+		{LocaleGerman, ScalePercent, math.NaN(), 'f', 2, "NaN"},
+	}
+
+	for _, tt := range tests {
+		lf := &LocaleFormatter{Locale: tt.locale, Scale: tt.scale}
+		var got string
+		if tt.fmt == 0 {
+			got = lf.Format(tt.value)
+		} else {
+			got = lf.FormatPrecision(tt.value, tt.fmt, tt.prec)
+		}
+		if got != tt.want {
+			t.Errorf("%+v scale %d: %v %c/%d = %q, want %q", tt.locale.Grouping, tt.scale, tt.value, tt.fmt, tt.prec, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleFormatterMatchesASCII(t *testing.T) {
+	plain := Locale{Decimal: ".", Minus: "-", Exponent: "e", Percent: "%"}
+	lf := NewLocaleFormatter(plain)
+	percent := &LocaleFormatter{Locale: plain, Scale: ScalePercent}
+	js := FormatOptions{Dialect: DialectJavaScript}
+	rng := rand.New(rand.NewSource(46))
+
+	for _, f := range generateMixed(20000) {
+		if want, _ := js.AppendFloat(nil, f); lf.Format(f) != string(want) {
+			t.Fatalf("Format(%v) = %s, want %s", f, lf.Format(f), want)
+		}
+
+		format := "eEfgG"[rng.Intn(5)]
+		prec := rng.Intn(20)
+		if got, want := lf.FormatPrecision(f, format, prec), strconv.FormatFloat(f, format, prec, 64); got != want {
+			t.Fatalf("FormatPrecision(%v, %c, %d) = %s, want %s", f, format, prec, got, want)
+		}
+
+		// Percent 'f' output is the 'f' output at prec+2 with the point moved
+		if math.Abs(f) < 1e15 {
+			digits := strconv.FormatFloat(f, 'f', prec+2, 64)
+			point := strings.IndexByte(digits, '.')
+			shifted := digits[:point] + digits[point+1:point+3]
+			if prec > 0 {
+				shifted += "." + digits[point+3:]
+			}
+			shifted = strings.TrimLeft(strings.TrimPrefix(shifted, "-"), "0")
+			if shifted == "" || shifted[0] == '.' {
+				shifted = "0" + shifted
+			}
+			if math.Signbit(f) {
+				shifted = "-" + shifted
+			}
+			if got := percent.FormatPrecision(f, 'f', prec); got != shifted+"%" {
+				t.Fatalf("percent FormatPrecision(%v, 'f', %d) = %s, want %s%%", f, prec, got, shifted)
+			}
+		}
+	}
+}
+
+func TestDecimalRounding(t *testing.T) {
+	tests := []struct {
+		value float64
+		round func(Decimal) Decimal
+		want  string
+	}{
+		{123456, func(d Decimal) Decimal { return d.RoundSig(3, RoundHalfEven) }, "123000"},
+		{0.012345, func(d Decimal) Decimal { return d.RoundSig(3, RoundHalfEven) }, "0.0123"},
+		{0.125, func(d Decimal) Decimal { return d.RoundSig(2, RoundHalfEven) }, "0.12"},
+		{0.125, func(d Decimal) Decimal { return d.RoundSig(2, RoundHalfUp) }, "0.13"},
+		{-0.135, func(d Decimal) Decimal { return d.RoundSig(2, RoundHalfEven) }, "-0.14"},
+		{2.675, func(d Decimal) Decimal { return d.RoundSig(3, RoundHalfEven) }, "2.68"},
+		{-0.135, func(d Decimal) Decimal { return d.RoundSig(2, RoundHalfUp) }, "-0.14"},
+		{2.5, func(d Decimal) Decimal { return d.RoundSig(1, RoundHalfEven) }, "2"},
+		{2.5, func(d Decimal) Decimal { return d.RoundSig(1, RoundHalfUp) }, "3"},
+		{999.5, func(d Decimal) Decimal { return d.RoundSig(3, RoundHalfEven) }, "1000"},
+		{9.87654321e-200, func(d Decimal) Decimal { return d.RoundSig(4, RoundHalfEven) }, "9.877e-200"},
+		{math.Pi, func(d Decimal) Decimal { return d.RoundSig(0, RoundHalfEven) }, "3"},
+		{math.Pi, func(d Decimal) Decimal { return d.RoundSig(30, RoundHalfEven) }, "3.141592653589793"},
+		{1.005, func(d Decimal) Decimal { return d.RoundFixed(2, RoundHalfEven) }, "1"},
+		{1.005, func(d Decimal) Decimal { return d.RoundFixed(2, RoundHalfUp) }, "1.01"},
+		{1.015, func(d Decimal) Decimal { return d.RoundFixed(2, RoundHalfEven) }, "1.02"},
+		{1234.5, func(d Decimal) Decimal { return d.RoundFixed(-2, RoundHalfEven) }, "1200"},
+		{1250, func(d Decimal) Decimal { return d.RoundFixed(-2, RoundHalfEven) }, "1200"},
+		{1250, func(d Decimal) Decimal { return d.RoundFixed(-2, RoundHalfUp) }, "1300"},
+		{-0.004, func(d Decimal) Decimal { return d.RoundFixed(2, RoundHalfUp) }, "0"},
+		{0.0005, func(d Decimal) Decimal { return d.RoundFixed(3, RoundHalfUp) }, "0.001"},
+		{1e-300, func(d Decimal) Decimal { return d.RoundFixed(2, RoundHalfUp) }, "0"},
+		{1e300, func(d Decimal) Decimal { return d.RoundFixed(2, RoundHalfUp) }, "1e300"},
+	}
+
+	for _, tt := range tests {
+		d, err := ToDecimal(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tt.round(d).String(); got != tt.want {
+			t.Errorf("rounding %v = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := ToDecimal(f); !errors.Is(err, ErrNonFinite) {
+			t.Errorf("ToDecimal(%v) error = %v", f, err)
+		}
+	}
+	if d, _ := ToDecimal(math.Copysign(0, -1)); d.String() != "-0" {
+		t.Errorf("ToDecimal(-0) = %s", d)
+	}
+}
+
+func TestDecimalRoundSigMatchesStrconv(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	for i := 0; i < 100000; i++ {
+		f := math.Float64frombits(rng.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) || f == 0 {
+			continue
+		}
+		d, _ := ToDecimal(f)
+		n := 1 + rng.Intn(17)
+		rounded := d.RoundSig(n, RoundHalfEven)
+
+		// strconv rounds the exact binary value, which only differs from
+		// rounding the shortest digits when those end exactly on a tie
+		digits := strconv.FormatFloat(f, 'e', -1, 64)
+		if mantissa := digits[:strings.IndexByte(digits, 'e')]; strings.HasSuffix(mantissa, "5") && len(strings.ReplaceAll(strings.TrimPrefix(mantissa, "-"), ".", "")) == n+1 {
+			continue
+		}
+		want, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'e', n-1, 64), 64)
+		if got, _ := strconv.ParseFloat(rounded.String(), 64); got != want {
+			t.Fatalf("%v RoundSig(%d) = %s, want %v", f, n, rounded, want)
+		}
+	}
+}
+
+func TestDecimalBigFloat(t *testing.T) {
+	for _, f := range append(generateMixed(5000), math.MaxFloat64, math.SmallestNonzeroFloat64, -1e-310, 123456789012345678) {
+		d, _ := ToDecimal(f)
+		if back, _ := d.BigFloat(53).Float64(); back != f {
+			t.Fatalf("ToDecimal(%v).BigFloat(53) = %v", f, back)
+		}
+		// big.Float has no subnormals, so those need more than float64's digits
+		if math.Abs(f) < 0x1p-1022 {
+			continue
+		}
+		fromBig, err := DecimalFromBigFloat(big.NewFloat(f))
+		if err != nil || fromBig != d {
+			t.Fatalf("DecimalFromBigFloat(%v) = %+v, %v; want %+v", f, fromBig, err, d)
+		}
+	}
+
+	// Long expansions are cut to 19 significant digits
+	third := new(big.Float).SetPrec(200).Quo(big.NewFloat(1), big.NewFloat(3))
+	if d, _ := DecimalFromBigFloat(third); d.String() != "0.3333333333333333333" {
+		t.Errorf("DecimalFromBigFloat(1/3) = %s", d)
+	}
+	if d := (Decimal{Mantissa: 12345, Exponent: 20}); d.BigFloat(0).Text('f', 0) != "1234500000000000000000000" {
+		t.Errorf("BigFloat(0) of 1.2345e24 = %s", d.BigFloat(0).Text('f', 0))
+	}
+	if _, err := DecimalFromBigFloat(new(big.Float).SetInf(true)); !errors.Is(err, ErrNonFinite) {
+		t.Errorf("DecimalFromBigFloat(-Inf) error = %v", err)
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	inputs := []string{
+		"0", "-0", "+0", "1", "-1", "0.5", ".5", "5.", "1e23", "8.988465674311579e+307",
+		"1.7976931348623157e308", "1.7976931348623159e308", "2e308", "-1e400",
+		"4.9406564584124654e-324", "2.4703282292062327e-324", "2.4703282292062328e-324",
+		"2.2250738585072011e-308", "2.2250738585072014e-308", "1e-400", "123456789012345678901234567890",
+		"9007199254740993", "9007199254740992.5", "0.1", "0.30000000000000004", "1E5", "1e+05",
+		"7.038531e-26", "1448997445238699", "0.000000000000000000000000000000000000000001",
+		"2.22507385850720113605740979670913197593481954635164564e-308",
+		"+Inf", "-Inf", "Inf", "infinity", "NaN", "nan",
+	}
+	for _, s := range inputs {
+		want, wantErr := strconv.ParseFloat(s, 64)
+		got, err := ParseFloat(s)
+		if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("ParseFloat(%q) = %v, want %v", s, got, want)
+		}
+		if (err != nil) != (wantErr != nil) {
+			t.Errorf("ParseFloat(%q) error = %v, want %v", s, err, wantErr)
+		}
+	}
+
+	for _, s := range []string{"", "+", "-", ".", "e5", "1e", "1e+", "1.2.3", "1_000", "0x10", " 1", "1 ", "+NaN", "--1"} {
+		if _, err := ParseFloat(s); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseFloat(%q) error = %v, want ErrSyntax", s, err)
+		}
+	}
+	if _, err := ParseFloat("1e999"); !errors.Is(err, ErrRange) {
+		t.Errorf("ParseFloat(1e999) error = %v, want ErrRange", err)
+	}
+
+	// Random digit strings, including long mantissas and near-halfway cases
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < 100000; i++ {
+		var sb strings.Builder
+		if rng.Intn(2) == 0 {
+			sb.WriteByte('-')
+		}
+		digits := 1 + rng.Intn(30)
+		point := rng.Intn(digits + 1)
+		for d := 0; d < digits; d++ {
+			if d == point && d > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteByte(byte('0' + rng.Intn(10)))
+		}
+		if rng.Intn(2) == 0 {
+			fmt.Fprintf(&sb, "e%d", rng.Intn(700)-350)
+		}
+		s := sb.String()
+		want, _ := strconv.ParseFloat(s, 64)
+		if got, _ := ParseFloat(s); math.Float64bits(got) != math.Float64bits(want) {
+			t.Fatalf("ParseFloat(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	samples := int(*differentialSamples / 4)
+	if testing.Short() {
+		samples = 1 << 14
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	buf := make([]byte, 0, 64)
+	for i := 0; i < samples; i++ {
+		f := math.Float64frombits(rng.Uint64())
+		if math.IsNaN(f) {
+			continue
+		}
+
+		// Every formatter's output parses back to the same bits
+		outputs := []string{db.Convert(f), string(AppendFloat(buf[:0], f)), db.Format(f, 'e', -1), db.Format(f, 'e', 16)}
+		for _, s := range outputs {
+			got, err := ParseFloat(s)
+			if math.Float64bits(got) != math.Float64bits(f) || (err != nil && !math.IsInf(f, 0)) {
+				t.Fatalf("ParseFloat(%q) = %v (%v), want %v", s, got, err, f)
+			}
+		}
+	}
+}
+
+func TestParseFloats(t *testing.T) {
+	db := NewUnifiedDragonbox()
+	values := generateMixed(5000)
+	strs := db.BatchConvert(values)
+
+	parsed, err := ParseFloats(strs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byteStrs := make([][]byte, len(strs))
+	for i, s := range strs {
+		byteStrs[i] = []byte(s)
+	}
+	parsedBytes, err := ParseFloatsBytes(byteStrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, f := range values {
+		if parsed[i] != f || parsedBytes[i] != f {
+			t.Fatalf("element %d: ParseFloats %v, ParseFloatsBytes %v, want %v", i, parsed[i], parsedBytes[i], f)
+		}
+	}
+
+	strs[4321] = "12x"
+	strs[4999] = ""
+	if _, err := ParseFloats(strs); err == nil || !errors.Is(err, ErrSyntax) || !strings.Contains(err.Error(), "element 4321") {
+		t.Errorf("ParseFloats error = %v, want ErrSyntax at element 4321", err)
+	}
+}
+
+// halfwayDecimal is the exact decimal midway between f and the next float64
+// up, e.g. 1.00000000000000011102230246251565404236316680908203125 for 1
+func halfwayDecimal(f float64) string {
+	lo := new(big.Float).SetPrec(2000).SetFloat64(f)
+	hi := new(big.Float).SetPrec(2000).SetFloat64(math.Nextafter(f, math.Inf(1)))
+	mid := new(big.Float).SetPrec(2000).Add(lo, hi)
+	mid.Quo(mid, big.NewFloat(2))
+	return mid.Text('e', -1)
+}
+
+func TestParseLongInputs(t *testing.T) {
+	zeros := 400000
+	if testing.Short() {
+		zeros = 20000
+	}
+
+	// Halfway points with 17 to 767 significant digits, then a long tail
+	// that is all zeros (ties to even) or ends in a 1 (rounds up)
+	for _, f := range []float64{1, 0.1, 1e300, 0x1p-1022, math.SmallestNonzeroFloat64, 3 * math.SmallestNonzeroFloat64, math.MaxFloat64 / 2} {
+		half := halfwayDecimal(f)
+		mantissa, exponent, _ := strings.Cut(half, "e")
+		for _, tail := range []string{"", strings.Repeat("0", zeros), strings.Repeat("0", zeros) + "1", "0000000001" + strings.Repeat("0", zeros)} {
+			input := mantissa + tail + "e" + exponent
+			want, _ := strconv.ParseFloat(input, 64)
+			if got, err := ParseFloat(input); got != want || err != nil {
+				t.Errorf("ParseFloat(halfway above %v + %d-digit tail) = %v, %v; want %v", f, len(tail), got, err, want)
+			}
+		}
+	}
+
+	// Long integers and leading zeros move the point of truncated digits
+	long := "1" + strings.Repeat("0", 299) + "." + strings.Repeat("9", zeros)
+	if got, _ := ParseFloat(long); got != 1e299 {
+		t.Errorf("ParseFloat(1e299 + 0.999...) = %v, want 1e299", got)
+	}
+	small := "0." + strings.Repeat("0", 400) + "5" + strings.Repeat("3", zeros) + "e100"
+	want, _ := strconv.ParseFloat(small, 64)
+	if got, err := ParseFloat(small); got != want || err != nil {
+		t.Errorf("ParseFloat(0.000...5333e100) = %v, %v; want %v", got, err, want)
+	}
+}
+
+func BenchmarkParseLong(b *testing.B) {
+	half, exponent, _ := strings.Cut(halfwayDecimal(1), "e")
+	input := half + strings.Repeat("0", 400000) + "1e" + exponent
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ParseFloat(input)
+	}
+}
+
+func BenchmarkParseFloat(b *testing.B) {
+	db := NewUnifiedDragonbox()
+	strs := db.BatchConvert(generateMixed(1000))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParseFloat(strs[i%len(strs)])
+	}
+}
+
 func BenchmarkDragonboxConversion(b *testing.B) {
 	db := NewUnifiedDragonbox()
 	testValues := []float64{
@@ -254,18 +2468,19 @@ func TestComprehensiveAdaptiveFeatures(t *testing.T) {
 	fmt.Printf("Total time: %v\n", duration)
 	fmt.Printf("Average per conversion: %v\n", duration/time.Duration(totalConversions))
 	
-	fmt.Printf("\n%s\n", db.GetPerformanceReport())
+	fmt.Printf("\n%s\n", db.Stats())
 	
 	// Verify adaptive features worked
+	stats := db.Stats()
 	patternsUsed := 0
-	for _, count := range db.patternStats {
+	for _, count := range stats.Patterns {
 		if count > 0 {
 			patternsUsed++
 		}
 	}
 	
 	rangesUsed := 0
-	for _, count := range db.rangeStats {
+	for _, count := range stats.Ranges {
 		if count > 0 {
 			rangesUsed++
 		}
@@ -281,5 +2496,5 @@ func TestComprehensiveAdaptiveFeatures(t *testing.T) {
 	
 	fmt.Printf("✅ ADAPTIVE SUCCESS: Used %d patterns and %d ranges\n", patternsUsed, rangesUsed)
 	fmt.Printf("✅ CACHE PERFORMANCE: %.1f%% hit rate\n", 
-		float64(db.cacheHits)*100/float64(db.cacheHits+db.cacheMisses+1))
+		stats.CacheHitRate()*100)
 }
\ No newline at end of file